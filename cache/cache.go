@@ -0,0 +1,108 @@
+// Package cache implements a content-addressed store used to reuse analysis results between runs.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"codacy/cli-v2/constants"
+)
+
+// Store keeps JSON entries on disk, addressed by keys computed with Key
+type Store struct {
+	directory string
+}
+
+// NewStore creates a store that keeps its entries in the given directory
+func NewStore(directory string) *Store {
+	return &Store{directory: directory}
+}
+
+// Directory returns the directory where the entries are kept
+func (s *Store) Directory() string {
+	return s.directory
+}
+
+// Key computes a key from the given parts. Different parts always produce different keys.
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(hash, "%d:%s\x00", len(part), part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// HashFile returns the hex encoded SHA-256 of the content of a file
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Get reads the entry of a key into value. It returns false when there is no entry for the key.
+// Unreadable entries are treated as missing, so a corrupted cache never breaks an analysis.
+func (s *Store) Get(key string, value interface{}) bool {
+	data, err := os.ReadFile(s.entryPath(key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// Put stores value as the entry of a key. Entries are written atomically, so concurrent readers
+// never see partially written entries.
+func (s *Store) Put(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	entryPath := s.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(entryPath), constants.DefaultDirPerms); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(entryPath), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), entryPath); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clear removes every entry of the store
+func (s *Store) Clear() error {
+	if err := os.RemoveAll(s.directory); err != nil {
+		return fmt.Errorf("failed to clear cache %s: %w", s.directory, err)
+	}
+	return nil
+}
+
+// entryPath spreads the entries over subdirectories, so no directory gets too many files
+func (s *Store) entryPath(key string) string {
+	return filepath.Join(s.directory, key[:2], key+".json")
+}
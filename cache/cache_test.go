@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	Value string `json:"value"`
+}
+
+func TestStorePutGetAndClear(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "cache"))
+	key := Key("eslint", "8.57.0", "file-hash")
+
+	var missing entry
+	assert.False(t, store.Get(key, &missing))
+
+	require.NoError(t, store.Put(key, entry{Value: "cached"}))
+
+	var found entry
+	assert.True(t, store.Get(key, &found))
+	assert.Equal(t, "cached", found.Value)
+
+	require.NoError(t, store.Clear())
+	assert.False(t, store.Get(key, &found))
+}
+
+func TestStoreIgnoresCorruptedEntries(t *testing.T) {
+	store := NewStore(t.TempDir())
+	key := Key("corrupted")
+	require.NoError(t, store.Put(key, entry{Value: "ok"}))
+	require.NoError(t, os.WriteFile(store.entryPath(key), []byte("{not json"), 0644))
+
+	var found entry
+	assert.False(t, store.Get(key, &found))
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, Key("a", "b"), Key("a", "b"))
+	assert.NotEqual(t, Key("a", "b"), Key("b", "a"))
+	assert.NotEqual(t, Key("ab", "c"), Key("a", "bc"), "parts must not be ambiguous")
+}
+
+func TestHashFile(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.txt")
+	second := filepath.Join(dir, "second.txt")
+	require.NoError(t, os.WriteFile(first, []byte("content"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("content"), 0644))
+
+	firstHash, err := HashFile(first)
+	require.NoError(t, err)
+	secondHash, err := HashFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, secondHash)
+
+	require.NoError(t, os.WriteFile(second, []byte("changed"), 0644))
+	secondHash, err = HashFile(second)
+	require.NoError(t, err)
+	assert.NotEqual(t, firstHash, secondHash)
+
+	_, err = HashFile(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
@@ -8,15 +8,19 @@ import (
 	"codacy/cli-v2/domain"
 	"codacy/cli-v2/plugins"
 	"codacy/cli-v2/tools"
-	"codacy/cli-v2/tools/lizard"
-	reviveTool "codacy/cli-v2/tools/revive"
+	_ "codacy/cli-v2/tools/lizard"
+	_ "codacy/cli-v2/tools/revive"
 	"codacy/cli-v2/utils/logger"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"codacy/cli-v2/utils"
 
@@ -34,6 +38,13 @@ var outputFormat string
 var sarifPath string
 var commitUuid string
 var projectToken string
+var analysisJobs int
+var changedSince string
+var newLinesOnly bool
+var updateBaseline bool
+var noBaseline bool
+var noCache bool
+var nullRules bool
 
 // LanguagesConfig represents the structure of the languages configuration file
 type LanguagesConfig struct {
@@ -174,51 +185,7 @@ func FilterToolsByLanguageSupport(tools map[string]*plugins.ToolInfo, files []st
 	return result
 }
 
-type Sarif struct {
-	Runs []struct {
-		Tool struct {
-			Driver struct {
-				Name    string `json:"name"`
-				Version string `json:"version"`
-				Rules   []struct {
-					ID               string `json:"id"`
-					HelpURI          string `json:"helpUri"`
-					ShortDescription struct {
-						Text string `json:"text"`
-					} `json:"shortDescription"`
-				} `json:"rules"`
-			} `json:"driver"`
-		} `json:"tool"`
-		Artifacts []struct {
-			Location struct {
-				URI string `json:"uri"`
-			} `json:"location"`
-		} `json:"artifacts"`
-		Results []struct {
-			Level   string `json:"level"`
-			Message struct {
-				Text string `json:"text"`
-			} `json:"message"`
-			Locations []struct {
-				PhysicalLocation struct {
-					ArtifactLocation struct {
-						URI   string `json:"uri"`
-						Index int    `json:"index"`
-					} `json:"artifactLocation"`
-					Region struct {
-						StartLine   int `json:"startLine"`
-						StartColumn int `json:"startColumn"`
-						EndLine     int `json:"endLine"`
-						EndColumn   int `json:"endColumn"`
-					} `json:"region"`
-				} `json:"physicalLocation"`
-			} `json:"locations"`
-			RuleID    string `json:"ruleId"`
-			RuleIndex int    `json:"ruleIndex"`
-		} `json:"results"`
-	} `json:"runs"`
-}
-
+// CodacyIssue is an issue as Codacy shows it, with the Codacy pattern of its rule
 type CodacyIssue struct {
 	Source   string `json:"source"`
 	Line     int    `json:"line"`
@@ -231,34 +198,23 @@ type CodacyIssue struct {
 func init() {
 	analyzeCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file for analysis results")
 	analyzeCmd.Flags().StringVarP(&toolsToAnalyzeParam, "tool", "t", "", "Which tool to run analysis with. If not specified, all configured tools will be run")
-	analyzeCmd.Flags().StringVar(&outputFormat, "format", "", "Output format (use 'sarif' for SARIF format)")
+	analyzeCmd.Flags().StringVar(&outputFormat, "format", "text", outputFormatsDescription())
 	analyzeCmd.Flags().BoolVar(&autoFix, "fix", false, "Apply auto fix to your issues when available")
+	analyzeCmd.Flags().IntVarP(&analysisJobs, "jobs", "j", 1, "Number of tools to run in parallel")
+	analyzeCmd.Flags().StringVar(&changedSince, "changed-since", "", "Only analyze the files changed since the given git ref (e.g. origin/main)")
+	analyzeCmd.Flags().BoolVar(&newLinesOnly, "new-lines-only", false, "Only report results on lines changed since --changed-since (not supported with --format native)")
+	analyzeCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "Write all current results to .codacy/baseline.json so they are not reported by later runs")
+	analyzeCmd.Flags().BoolVar(&noBaseline, "no-baseline", false, "Report all results, ignoring .codacy/baseline.json")
+	analyzeCmd.Flags().StringArrayVar(&toolTimeoutFlags, "tool-timeout", nil, "Maximum time a tool may run, for every tool (e.g. 10m) or a single one (e.g. pmd=20m); can be repeated")
+	analyzeCmd.Flags().BoolVar(&noCache, "no-cache", false, "Analyze every file, ignoring the results cached by previous runs")
+	analyzeCmd.Flags().StringVar(&failOnLevel, "fail-on", "", "Exit with code 3 when issues of this level or higher are found (error, warning or note)")
+	analyzeCmd.Flags().IntVar(&maxIssues, "max-issues", 0, "Exit with code 3 when more than this number of issues is found")
+	analyzeCmd.Flags().BoolVar(&nullRules, "null-rules", false, "Set the rules of every tool to null in the SARIF output, as in previous versions")
+	analyzeCmd.Flags().BoolVar(&failOnToolError, "fail-on-tool-error", false, "Exit with code 4 when a tool fails to run")
 	cmdutils.AddCloudFlags(analyzeCmd, &initFlags)
 	rootCmd.AddCommand(analyzeCmd)
 }
 
-func loadsToolAndPatterns(toolName string, onlyEnabledPatterns bool) (domain.Tool, []domain.PatternConfiguration) {
-	var toolsResponse, err = codacyclient.GetToolsVersions()
-	if err != nil {
-		fmt.Println("Error:", err)
-		return domain.Tool{}, []domain.PatternConfiguration{}
-	}
-	var tool domain.Tool
-	for _, t := range toolsResponse {
-		if t.Name == toolName {
-			tool = t
-			break
-		}
-	}
-	var patterns []domain.PatternConfiguration
-	patterns, err = codacyclient.GetToolPatternsConfig(domain.InitFlags{}, tool.Uuid, onlyEnabledPatterns)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return domain.Tool{}, []domain.PatternConfiguration{}
-	}
-	return tool, patterns
-}
-
 var versionedToolNames = map[string]map[int]string{
 	"eslint": {
 		7: "ESLint (deprecated)",
@@ -365,74 +321,56 @@ func checkIfConfigExistsAndIsNeeded(toolName string, cliLocalMode bool) error {
 	return nil
 }
 
-func runToolByName(toolName string, workDirectory string, pathsToCheck []string, autoFix bool, outputFile string, outputFormat string, tool *plugins.ToolInfo, runtime *plugins.RuntimeInfo, cliLocalMode bool) error {
-	var t *domain.Tool
-	var usesConfigurationFile = false
-
-	//Check if the user is using the repository configuration file
-	// If the user doesn't provide init flags, we skip fetching repository tools
-	if initFlags != (domain.InitFlags{}) {
-		// Get the tool name with the right version e.g. ESLint9, PMD7, etc.
-		toolRightVersion := getToolName(toolName, tool.Version)
-
-		// Get the repository tools to access tool settings
-		repositoryTools, _ := codacyclient.GetRepositoryTools(initFlags)
+// preparedTool holds everything needed to execute a tool once it is installed and configured
+type preparedTool struct {
+	name                  string
+	tool                  *plugins.ToolInfo
+	runtime               *plugins.RuntimeInfo
+	usesConfigurationFile bool
+	// timeout is the maximum time the tool may run, 0 when it has no timeout
+	timeout time.Duration
+}
 
-		// Find the matching tool in repositoryTools
-		for i, tool := range repositoryTools {
-			if tool.Name == toolRightVersion {
-				t = &repositoryTools[i]
-				usesConfigurationFile = t.Settings.UsesConfigurationFile
-				break
-			}
-		}
+// runToolByName dispatches the prepared tool to the runner registered for it, stopping it once its timeout expires
+func runToolByName(ctx context.Context, prepared *preparedTool, workDirectory string, pathsToCheck []string, autoFix bool, outputFile string, outputFormat string, stdout io.Writer, stderr io.Writer) error {
+	runner, ok := tools.GetRunner(prepared.name)
+	if !ok {
+		return fmt.Errorf("unsupported tool: %s", prepared.name)
 	}
 
-	// If the user is not using configuration file from repository, we check if there's a local one
-	if !usesConfigurationFile {
-		err := checkIfConfigExistsAndIsNeeded(toolName, cliLocalMode)
-		if err != nil {
-			return err
-		}
+	if prepared.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, prepared.timeout)
+		defer cancel()
 	}
 
-	switch toolName {
-	case "eslint":
-		binaryPath := runtime.Binaries[tool.Runtime]
-		return tools.RunEslint(workDirectory, tool.InstallDir, binaryPath, pathsToCheck, autoFix, outputFile, outputFormat, usesConfigurationFile)
-	case "trivy":
-		binaryPath := tool.Binaries[toolName]
-		return tools.RunTrivy(workDirectory, binaryPath, pathsToCheck, outputFile, outputFormat)
-	case "pmd":
-		binaryPath := tool.Binaries[toolName]
-		return tools.RunPmd(workDirectory, binaryPath, pathsToCheck, outputFile, outputFormat, config.Config)
-	case "pylint":
-		binaryPath := tool.Binaries[tool.Runtime]
-		return tools.RunPylint(workDirectory, binaryPath, pathsToCheck, outputFile, outputFormat)
-	case "dartanalyzer":
-		binaryPath := tool.Binaries[tool.Runtime]
-		return tools.RunDartAnalyzer(workDirectory, tool.InstallDir, binaryPath, pathsToCheck, outputFile, outputFormat)
-	case "opengrep":
-		binaryPath := tool.Binaries[toolName]
-		return tools.RunOpengrep(workDirectory, binaryPath, pathsToCheck, outputFile, outputFormat)
-	case "lizard":
-		binaryPath := tool.Binaries[tool.Runtime]
-		return lizard.RunLizard(workDirectory, binaryPath, pathsToCheck, outputFile, outputFormat)
-	case "codacy-enigma-cli":
-		return tools.RunEnigma(workDirectory, tool.InstallDir, tool.Binaries["codacy-enigma-cli"], pathsToCheck, outputFile, outputFormat)
-	case "revive":
-		return reviveTool.RunRevive(workDirectory, tool.Binaries["revive"], pathsToCheck, outputFile, outputFormat)
+	_, err := runner.Run(ctx, &tools.RunRequest{
+		WorkDirectory:         workDirectory,
+		PathsToCheck:          pathsToCheck,
+		OutputFile:            outputFile,
+		OutputFormat:          outputFormat,
+		AutoFix:               autoFix,
+		Tool:                  prepared.tool,
+		Runtime:               prepared.runtime,
+		UsesConfigurationFile: prepared.usesConfigurationFile,
+		Stdout:                stdout,
+		Stderr:                stderr,
+	})
+	// Some runners treat a killed process like one that found issues, so check the context first
+	if contextErr := toolRunContextError(ctx, prepared); contextErr != nil {
+		return contextErr
 	}
-	return fmt.Errorf("unsupported tool: %s", toolName)
+	return err
 }
 
-func runTool(workDirectory string, toolName string, pathsToCheck []string, outputFile string, autoFix bool, outputFormat string, cliLocalMode bool) error {
+// prepareTool makes sure a tool, its runtime and its configuration are ready to be executed.
+// It installs and configures tools, so it must not be called concurrently.
+func prepareTool(toolName string, cliLocalMode bool) (*preparedTool, error) {
 	err := validateToolName(toolName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	log.Println("Running tools for the specified file(s)...")
-	log.Printf("Running %s...", toolName)
+	log.Printf("Preparing %s...", toolName)
 
 	tool := config.Config.Tools()[toolName]
 	var isToolInstalled bool
@@ -458,7 +396,7 @@ func runTool(workDirectory string, toolName string, pathsToCheck []string, outpu
 		}
 		err := config.InstallTool(toolName, tool, "")
 		if err != nil {
-			return fmt.Errorf("failed to install %s: %w", toolName, err)
+			return nil, fmt.Errorf("failed to install %s: %w", toolName, err)
 		}
 		tool = config.Config.Tools()[toolName]
 		runtime = config.Config.Runtimes()[tool.Runtime]
@@ -467,7 +405,7 @@ func runTool(workDirectory string, toolName string, pathsToCheck []string, outpu
 			fmt.Printf("%s runtime is not installed, installing...", tool.Runtime)
 			err := config.InstallRuntime(tool.Runtime, runtime)
 			if err != nil {
-				return fmt.Errorf("failed to install %s runtime: %w", tool.Runtime, err)
+				return nil, fmt.Errorf("failed to install %s runtime: %w", tool.Runtime, err)
 			}
 			runtime = config.Config.Runtimes()[tool.Runtime]
 		}
@@ -479,12 +417,62 @@ func runTool(workDirectory string, toolName string, pathsToCheck []string, outpu
 			fmt.Printf("%s runtime is not installed, installing...", tool.Runtime)
 			err := config.InstallRuntime(tool.Runtime, runtime)
 			if err != nil {
-				return fmt.Errorf("failed to install %s runtime: %w", tool.Runtime, err)
+				return nil, fmt.Errorf("failed to install %s runtime: %w", tool.Runtime, err)
 			}
 			runtime = config.Config.Runtimes()[tool.Runtime]
 		}
 	}
-	return runToolByName(toolName, workDirectory, pathsToCheck, autoFix, outputFile, outputFormat, tool, runtime, cliLocalMode)
+
+	var usesConfigurationFile = false
+
+	//Check if the user is using the repository configuration file
+	// If the user doesn't provide init flags, we skip fetching repository tools
+	if initFlags != (domain.InitFlags{}) {
+		// Get the tool name with the right version e.g. ESLint9, PMD7, etc.
+		toolRightVersion := getToolName(toolName, tool.Version)
+
+		// Get the repository tools to access tool settings
+		repositoryTools, _ := codacyclient.GetRepositoryTools(initFlags)
+
+		// Find the matching tool in repositoryTools
+		for _, repositoryTool := range repositoryTools {
+			if repositoryTool.Name == toolRightVersion {
+				usesConfigurationFile = repositoryTool.Settings.UsesConfigurationFile
+				break
+			}
+		}
+	}
+
+	// If the user is not using configuration file from repository, we check if there's a local one
+	if !usesConfigurationFile {
+		err := checkIfConfigExistsAndIsNeeded(toolName, cliLocalMode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &preparedTool{
+		name:                  toolName,
+		tool:                  tool,
+		runtime:               runtime,
+		usesConfigurationFile: usesConfigurationFile,
+	}, nil
+}
+
+// prepareTools prepares the given tools sequentially, returning the tools ready to run
+// and a failed result for each tool that couldn't be prepared
+func prepareTools(toolNames []string, cliLocalMode bool) ([]*preparedTool, []toolRunResult) {
+	var prepared []*preparedTool
+	var failed []toolRunResult
+	for _, toolName := range toolNames {
+		p, err := prepareTool(toolName, cliLocalMode)
+		if err != nil {
+			failed = append(failed, toolRunResult{toolName: toolName, err: err})
+			continue
+		}
+		prepared = append(prepared, p)
+	}
+	return prepared, failed
 }
 
 // validatePaths checks if all provided paths exist and returns an error if any don't
@@ -513,12 +501,52 @@ Supports API token, provider, and repository flags to automatically fetch tool c
 			os.Exit(1)
 		}
 
+		if newLinesOnly && changedSince == "" {
+			fmt.Println("❌ Error: --new-lines-only requires --changed-since")
+			os.Exit(1)
+		}
+
+		if updateBaseline && (changedSince != "" || len(args) > 0 || toolsToAnalyzeParam != "") {
+			fmt.Println("❌ Error: --update-baseline must analyze the whole repository with all tools and can't be combined with paths, --tool or --changed-since")
+			os.Exit(1)
+		}
+
+		qualityGate, err := resolveQualityGate(cmd)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		toolTimeouts, err := resolveToolTimeouts(config.Config.ToolTimeouts(), toolTimeoutFlags)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stopSignals := analysisContext()
+		defer stopSignals()
+
 		// Get current working directory
 		workDirectory, err := os.Getwd()
 		if err != nil {
 			log.Fatalf("Failed to get current working directory: %v", err)
 		}
 
+		var changedLines utils.ChangedLines
+		if changedSince != "" {
+			changedLines, err = utils.GetChangedLines(workDirectory, changedSince)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				os.Exit(1)
+			}
+			args = changedFilesWithinPaths(changedLines.Files(), args)
+			if len(args) == 0 {
+				log.Printf("No files changed since %s. Skipping analysis.", changedSince)
+				return
+			}
+			log.Printf("Analyzing %d file(s) changed since %s", len(args), changedSince)
+		}
+
 		cliLocalMode := len(initFlags.ApiToken) == 0
 
 		var toolsToRun map[string]*plugins.ToolInfo
@@ -546,7 +574,38 @@ Supports API token, provider, and repository flags to automatically fetch tool c
 			return
 		}
 
-		if outputFormat == "sarif" {
+		toolNames := make([]string, 0, len(toolsToRun))
+		for toolName := range toolsToRun {
+			toolNames = append(toolNames, toolName)
+		}
+		sort.Strings(toolNames)
+
+		jobs := analysisJobs
+		if !rendersMergedResults(outputFormat) && outputFile != "" && jobs > 1 {
+			// All tools would write their native output to the same file
+			log.Println("Running tools sequentially as all tools write to the same output file")
+			jobs = 1
+		}
+
+		preparedTools, runResults := prepareTools(toolNames, cliLocalMode)
+		for _, prepared := range preparedTools {
+			prepared.timeout = toolTimeouts.For(prepared.name)
+		}
+
+		// pathsForTool returns the paths each tool should analyze; when analyzing changed files,
+		// each tool only gets the files it supports
+		var changedFilesByTool map[string][]string
+		if changedSince != "" {
+			changedFilesByTool = filesSupportedByTools(toolNames, args)
+		}
+		pathsForTool := func(toolName string) []string {
+			if changedFilesByTool != nil {
+				return changedFilesByTool[toolName]
+			}
+			return args
+		}
+
+		if rendersMergedResults(outputFormat) || updateBaseline {
 			// Create temporary directory for individual tool outputs
 			tmpDir, err := os.MkdirTemp("", "codacy-analysis-*")
 			if err != nil {
@@ -554,15 +613,29 @@ Supports API token, provider, and repository flags to automatically fetch tool c
 			}
 			defer os.RemoveAll(tmpDir)
 
+			toolSarifFile := func(toolName string) string {
+				return filepath.Join(tmpDir, fmt.Sprintf("%s.sarif", toolName))
+			}
+
 			var sarifOutputs []string
-			for toolName := range toolsToRun {
-				tmpFile := filepath.Join(tmpDir, fmt.Sprintf("%s.sarif", toolName))
-				if err := runTool(workDirectory, toolName, args, tmpFile, autoFix, outputFormat, cliLocalMode); err != nil {
-					log.Printf("Tool failed to run: %v\n", err)
-				}
-				sarifOutputs = append(sarifOutputs, tmpFile)
+			for _, prepared := range preparedTools {
+				sarifOutputs = append(sarifOutputs, toolSarifFile(prepared.name))
+			}
+
+			// Fixes change the analyzed files, so their results can't be reused
+			var cachePlans map[string]*toolCachePlan
+			if !noCache && !autoFix {
+				cachePlans = planResultsCache(resultsCacheStore(), preparedTools, workDirectory, pathsForTool)
 			}
 
+			toolRunResults := runToolsConcurrently(preparedTools, jobs, func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error {
+				return runToolWithCache(ctx, prepared, cachePlans[prepared.name], workDirectory, pathsForTool(prepared.name), autoFix, toolSarifFile(prepared.name), stdout, stderr)
+			}, os.Stdout, os.Stderr)
+			markCachedRuns(toolRunResults, cachePlans)
+			runResults = append(runResults, toolRunResults...)
+			logToolRunSummary(runResults)
+			exitIfInterrupted(ctx)
+
 			// create output file tmp file
 			tmpOutputFile := filepath.Join(tmpDir, "merged.sarif")
 
@@ -577,25 +650,86 @@ Supports API token, provider, and repository flags to automatically fetch tool c
 				log.Fatalf("Failed to read merged SARIF output: %v", err)
 			}
 
-			filteredData, err := utils.FilterRulesFromSarif(sarifData)
+			sarifData, err = utils.AddSarifFingerprints(sarifData, workDirectory)
 			if err != nil {
-				log.Fatalf("Failed to filter rules from SARIF: %v", err)
+				log.Fatalf("Failed to add fingerprints to SARIF: %v", err)
 			}
 
-			if outputFile != "" {
-				// Write filtered SARIF to output file
-				os.WriteFile(outputFile, filteredData, constants.DefaultFilePerms)
-			} else {
-				// Print the filtered SARIF output
-				fmt.Println(string(filteredData))
+			sarifData, err = applyInlineSuppressions(sarifData, workDirectory)
+			if err != nil {
+				log.Fatalf("Failed to apply codacy:ignore comments: %v", err)
+			}
+
+			baselinePath := filepath.Join(config.Config.LocalCodacyDirectory(), constants.BaselineFileName)
+			if updateBaseline {
+				if err := writeBaseline(sarifData, workDirectory, baselinePath); err != nil {
+					log.Fatalf("Failed to update baseline: %v", err)
+				}
+				// Only write the results when a format was explicitly requested
+				if !cmd.Flags().Changed("format") || !rendersMergedResults(outputFormat) {
+					return
+				}
+			} else if !noBaseline {
+				sarifData, err = applyBaseline(sarifData, workDirectory, baselinePath)
+				if err != nil {
+					log.Fatalf("Failed to apply baseline: %v", err)
+				}
+			}
+
+			if newLinesOnly {
+				sarifData, err = keepResultsOnChangedLines(sarifData, workDirectory, changedLines)
+				if err != nil {
+					log.Fatalf("Failed to filter results on changed lines: %v", err)
+				}
 			}
+
+			catalog := defaultPatternCatalog()
+			lookupPatterns := codacyPatternLookup(catalog.toolPatterns)
+			parseIssues := outputFormat != sarifOutputFormat || hasIssueThresholds(qualityGate)
+			filteredData, toolResults, err := processMergedResults(sarifData, workDirectory, nullRules, parseIssues, lookupPatterns)
+			if err != nil {
+				log.Fatalf("Failed to process analysis results: %v", err)
+			}
+			if outputFormat != sarifOutputFormat {
+				toolResults = addAnalyzedFiles(toolResults, analyzedFilesByTool(toolRunResults, workDirectory, pathsForTool))
+			}
+
+			switch outputFormat {
+			case sarifOutputFormat:
+				err = writeSarifOutput(filteredData, outputFile)
+			case codacyJSONOutputFormat:
+				var ruleMappings *userRuleMappings
+				if ruleMappings, err = loadRuleMappings(ruleMappingsPath()); err == nil {
+					err = writeCodacyJSONOutput(codacyIssues(toolResults, catalog.toolPatterns, catalog.pattern, ruleMappings), outputFile)
+				}
+			default:
+				err = writeFormattedOutput(outputFormat, toolResults, workDirectory, outputFile, lookupPatterns)
+			}
+			if err != nil {
+				log.Fatalf("Failed to write analysis results: %v", err)
+			}
+
+			enforceQualityGate(qualityGate, toolResults, runResults)
 		} else {
+			if newLinesOnly {
+				log.Println("Warning: --new-lines-only is not supported with the native output of the tools, reporting all results")
+			}
+			baselinePath := filepath.Join(config.Config.LocalCodacyDirectory(), constants.BaselineFileName)
+			if _, err := os.Stat(baselinePath); err == nil && !noBaseline {
+				log.Printf("Warning: %s is not applied to the native output of the tools, reporting all results", baselinePath)
+			}
+
 			// Run tools without merging outputs
-			for toolName := range toolsToRun {
-				if err := runTool(workDirectory, toolName, args, outputFile, autoFix, outputFormat, cliLocalMode); err != nil {
-					log.Printf("Tool failed to run: %v\n", err)
-				}
+			runResults = append(runResults, runToolsConcurrently(preparedTools, jobs, func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error {
+				return runToolByName(ctx, prepared, workDirectory, pathsForTool(prepared.name), autoFix, outputFile, nativeToolOutputFormat(outputFormat), stdout, stderr)
+			}, os.Stdout, os.Stderr)...)
+			logToolRunSummary(runResults)
+			exitIfInterrupted(ctx)
+
+			if hasIssueThresholds(qualityGate) {
+				log.Println("Warning: the quality gate issue thresholds are not checked with the native output of the tools")
 			}
+			enforceQualityGate(qualityGate, nil, runResults)
 		}
 	},
 }
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"codacy/cli-v2/utils"
+)
+
+// writeBaseline stores all the results of the SARIF report as the baseline
+func writeBaseline(sarifData []byte, baseDir string, baselinePath string) error {
+	baseline, err := utils.NewBaseline(sarifData, baseDir)
+	if err != nil {
+		return err
+	}
+	if err := baseline.Save(baselinePath); err != nil {
+		return err
+	}
+
+	log.Printf("Baseline with %d issue(s) written to %s", baseline.SuppressedIssues, baselinePath)
+	return nil
+}
+
+// applyBaseline removes the results that are part of the baseline, when a baseline file exists
+func applyBaseline(sarifData []byte, baseDir string, baselinePath string) ([]byte, error) {
+	baseline, err := utils.LoadBaseline(baselinePath)
+	if os.IsNotExist(err) {
+		return sarifData, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filteredData, suppressed, err := baseline.Apply(sarifData, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Suppressed %d issue(s) present in the baseline %s (baseline has %d issue(s))", suppressed, baselinePath, baseline.SuppressedIssues)
+	return filteredData, nil
+}
+
+// applyInlineSuppressions marks the results suppressed by codacy:ignore comments, which are kept in the SARIF output
+func applyInlineSuppressions(sarifData []byte, baseDir string) ([]byte, error) {
+	updatedData, suppressed, err := utils.ApplyInlineSuppressions(sarifData, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if suppressed > 0 {
+		log.Printf("Suppressed %d issue(s) with codacy:ignore comments", suppressed)
+	}
+	return updatedData, nil
+}
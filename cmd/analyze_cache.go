@@ -0,0 +1,488 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"codacy/cli-v2/cache"
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/tools"
+	"codacy/cli-v2/utils"
+	"codacy/cli-v2/version"
+)
+
+// maxCacheMissesPerRun is the number of files without cached results above which a tool analyzes its
+// original paths instead of receiving each of those files as an argument
+const maxCacheMissesPerRun = 200
+
+// toolsAnalyzingSingleTarget are the tools that accept a single path to analyze, so they can't be
+// restricted to the files without cached results
+var toolsAnalyzingSingleTarget = map[string]bool{
+	"trivy": true,
+}
+
+// toolsComparingFiles report findings spanning several files, such as the duplicated blocks of Lizard, so they
+// analyze all their files when any of them has no cached results
+var toolsComparingFiles = map[string]bool{
+	"lizard": true,
+}
+
+// resultsCacheStore returns the store of the analysis results cache, in the global Codacy directory
+func resultsCacheStore() *cache.Store {
+	return cache.NewStore(filepath.Join(config.Config.CodacyDirectory(), "results-cache"))
+}
+
+// cachedFileResults holds the SARIF results a tool reported for a single file, and the artifacts describing
+// that file, such as the file metrics of Lizard
+type cachedFileResults struct {
+	Results   []json.RawMessage `json:"results"`
+	Artifacts []json.RawMessage `json:"artifacts,omitempty"`
+}
+
+// cachedRun holds the SARIF run of a tool without its results and the artifacts of the analyzed files, used
+// to rebuild the output of the tool when every file has cached results
+type cachedRun struct {
+	Run map[string]json.RawMessage `json:"run"`
+}
+
+// toolCachePlan tells which files of a tool have cached results and which must be analyzed
+type toolCachePlan struct {
+	store    *cache.Store
+	runKey   string
+	fileKeys map[string]string
+	run      map[string]json.RawMessage
+	cached   map[string]cachedFileResults
+	missed   []string
+	// fromCache is set when the tool didn't run because every file had cached results
+	fromCache bool
+}
+
+// planResultsCache looks up the cached results of the files each prepared tool would analyze.
+// Tools without a plan must run without the cache, e.g. when their files can't be determined.
+func planResultsCache(store *cache.Store, preparedTools []*preparedTool, workDirectory string, pathsForTool func(toolName string) []string) map[string]*toolCachePlan {
+	repositoryFiles, err := utils.ListRepositoryFiles(workDirectory)
+	if err != nil {
+		log.Printf("Results cache disabled: %v", err)
+		return nil
+	}
+	langConfig, err := LoadLanguageConfig()
+	if err != nil {
+		log.Printf("Results cache disabled: %v", err)
+		return nil
+	}
+
+	repositoryFileSet := make(map[string]bool, len(repositoryFiles))
+	for _, file := range repositoryFiles {
+		repositoryFileSet[file] = true
+	}
+
+	fileHashes := make(map[string]string)
+	plans := make(map[string]*toolCachePlan, len(preparedTools))
+	for _, prepared := range preparedTools {
+		paths := pathsForTool(prepared.name)
+		if !pathsWithinRepositoryFiles(paths, workDirectory, repositoryFileSet) {
+			continue
+		}
+
+		files := filesAnalyzedByTool(prepared.name, changedFilesWithinPaths(repositoryFiles, paths), langConfig)
+		if len(files) == 0 {
+			continue
+		}
+
+		plan, err := newToolCachePlan(store, prepared, workDirectory, files, fileHashes)
+		if err != nil {
+			log.Printf("Results cache disabled for %s: %v", prepared.name, err)
+			continue
+		}
+		plans[prepared.name] = plan
+	}
+	return plans
+}
+
+// pathsWithinRepositoryFiles checks that the given files are known to git, so the files a tool analyzes
+// can be listed. Ignored files given explicitly would otherwise be left out of the analysis.
+func pathsWithinRepositoryFiles(paths []string, workDirectory string, repositoryFiles map[string]bool) bool {
+	for _, path := range paths {
+		if filepath.IsAbs(path) {
+			return false
+		}
+		info, err := os.Stat(filepath.Join(workDirectory, path))
+		if err != nil {
+			return false
+		}
+		if !info.IsDir() && !repositoryFiles[filepath.ToSlash(filepath.Clean(path))] {
+			return false
+		}
+	}
+	return true
+}
+
+// filesAnalyzedByTool keeps the files the languages configuration explicitly assigns to a tool, by extension
+// or file name. Unlike IsToolSupportedForFile, files are never assumed to be supported, as they may be given
+// to the tool as arguments. Tools without extensions in the configuration get no files.
+func filesAnalyzedByTool(toolName string, files []string, langConfig *LanguagesConfig) []string {
+	if langConfig == nil {
+		return nil
+	}
+
+	for _, tool := range langConfig.Tools {
+		if tool.Name != toolName {
+			continue
+		}
+
+		var result []string
+		for _, file := range files {
+			fileExt := GetFileExtension(file)
+			fileName := filepath.Base(file)
+			supported := false
+			for _, ext := range tool.Extensions {
+				if fileExt != "" && strings.EqualFold(ext, fileExt) {
+					supported = true
+					break
+				}
+			}
+			for _, name := range tool.Files {
+				if strings.EqualFold(name, fileName) {
+					supported = true
+					break
+				}
+			}
+			if supported {
+				result = append(result, file)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+// newToolCachePlan computes the cache keys of a tool and of each of its files, and reads their cached results
+func newToolCachePlan(store *cache.Store, prepared *preparedTool, workDirectory string, files []string, fileHashes map[string]string) (*toolCachePlan, error) {
+	toolVersion := ""
+	if prepared.tool != nil {
+		toolVersion = prepared.tool.Version
+	}
+
+	configHash := ""
+	if configFileName, ok := constants.ToolConfigFileNames[prepared.name]; ok {
+		if configFile, exists := tools.ConfigFileExists(config.Config, configFileName); exists {
+			hash, err := cache.HashFile(configFile)
+			if err != nil {
+				return nil, err
+			}
+			configHash = hash
+		}
+	}
+
+	runKey := cache.Key(version.GetVersion(), workDirectory, prepared.name, toolVersion, configHash, strconv.FormatBool(prepared.usesConfigurationFile))
+	plan := &toolCachePlan{
+		store:    store,
+		runKey:   runKey,
+		fileKeys: make(map[string]string, len(files)),
+		cached:   make(map[string]cachedFileResults),
+	}
+
+	var run cachedRun
+	if store.Get(runKey, &run) {
+		plan.run = run.Run
+	}
+
+	for _, file := range files {
+		hash, ok := fileHashes[file]
+		if !ok {
+			var err error
+			hash, err = cache.HashFile(filepath.Join(workDirectory, filepath.FromSlash(file)))
+			if err != nil {
+				return nil, err
+			}
+			fileHashes[file] = hash
+		}
+
+		key := cache.Key(runKey, file, hash)
+		plan.fileKeys[file] = key
+
+		var entry cachedFileResults
+		if store.Get(key, &entry) {
+			plan.cached[file] = entry
+		} else {
+			plan.missed = append(plan.missed, file)
+		}
+	}
+
+	return plan, nil
+}
+
+// analyzesAllPaths tells if the tool should analyze its original paths instead of only the files without cached results
+func (p *toolCachePlan) analyzesAllPaths(toolName string) bool {
+	return p.run == nil || len(p.cached) == 0 || len(p.missed) > maxCacheMissesPerRun || toolsAnalyzingSingleTarget[toolName] || toolsComparingFiles[toolName]
+}
+
+// runToolWithCache runs a tool writing its SARIF output to outputFile. With a cache plan, only the files without
+// cached results are analyzed, the cached results of the other files are added to the output, and the results
+// of the analyzed files are stored for the next runs.
+func runToolWithCache(ctx context.Context, prepared *preparedTool, plan *toolCachePlan, workDirectory string, pathsToCheck []string, autoFix bool, outputFile string, stdout io.Writer, stderr io.Writer) error {
+	if plan == nil {
+		return runToolByName(ctx, prepared, workDirectory, pathsToCheck, autoFix, outputFile, "sarif", stdout, stderr)
+	}
+
+	if len(plan.missed) == 0 && plan.run != nil {
+		if err := writeSarifRun(outputFile, plan.run, plan.cached); err == nil {
+			plan.fromCache = true
+			return nil
+		}
+	}
+
+	if plan.analyzesAllPaths(prepared.name) {
+		if err := runToolByName(ctx, prepared, workDirectory, pathsToCheck, autoFix, outputFile, "sarif", stdout, stderr); err != nil {
+			return err
+		}
+		if run, results, err := readSarifRunResults(outputFile, workDirectory, plan.fileKeys); err == nil {
+			plan.save(run, results, sortedKeys(plan.fileKeys))
+		}
+		return nil
+	}
+
+	log.Printf("%s: reusing cached results of %d file(s), analyzing %d changed file(s)", prepared.name, len(plan.cached), len(plan.missed))
+	if err := runToolByName(ctx, prepared, workDirectory, plan.missed, autoFix, outputFile, "sarif", stdout, stderr); err != nil {
+		return err
+	}
+
+	missed := make(map[string]string, len(plan.missed))
+	for _, file := range plan.missed {
+		missed[file] = plan.fileKeys[file]
+	}
+	run, results, err := readSarifRunResults(outputFile, workDirectory, missed)
+	if err != nil {
+		// The output can't be combined with the cached results, analyze everything instead
+		log.Printf("%s: can't reuse cached results (%v), analyzing all files", prepared.name, err)
+		return runToolByName(ctx, prepared, workDirectory, pathsToCheck, autoFix, outputFile, "sarif", stdout, stderr)
+	}
+	plan.save(run, results, plan.missed)
+
+	for file, fileResults := range plan.cached {
+		results[file] = fileResults
+	}
+	return writeSarifRun(outputFile, run, results)
+}
+
+// save stores the results of the analyzed files, including the files without results, and the run they belong to.
+// The run holds no results nor artifacts of the analyzed files, so a partial run doesn't drop the other files.
+func (p *toolCachePlan) save(run map[string]json.RawMessage, results map[string]cachedFileResults, analyzedFiles []string) {
+	for _, file := range analyzedFiles {
+		fileResults := results[file]
+		if fileResults.Results == nil {
+			fileResults.Results = []json.RawMessage{}
+		}
+		if err := p.store.Put(p.fileKeys[file], fileResults); err != nil {
+			log.Printf("Failed to store cached results: %v", err)
+			return
+		}
+	}
+	if err := p.store.Put(p.runKey, cachedRun{Run: run}); err != nil {
+		log.Printf("Failed to store cached results: %v", err)
+	}
+}
+
+// readSarifRunResults reads the single run of a SARIF file and groups its results, and the artifacts of the
+// expected files, by file. It fails when a result isn't located in one of the expected files, as such results
+// can't be attributed to a cache entry. Artifacts of other files, e.g. configuration files, stay in the run.
+func readSarifRunResults(sarifFile string, workDirectory string, expectedFiles map[string]string) (map[string]json.RawMessage, map[string]cachedFileResults, error) {
+	data, err := os.ReadFile(sarifFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var report utils.SimpleSarifReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SARIF output: %w", err)
+	}
+	if len(report.Runs) != 1 {
+		return nil, nil, fmt.Errorf("expected 1 run in SARIF output, found %d", len(report.Runs))
+	}
+
+	var run map[string]json.RawMessage
+	if err := json.Unmarshal(report.Runs[0], &run); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SARIF run: %w", err)
+	}
+
+	var rawResults []json.RawMessage
+	if resultsData, ok := run["results"]; ok {
+		if err := json.Unmarshal(resultsData, &rawResults); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SARIF results: %w", err)
+		}
+	}
+	delete(run, "results")
+
+	results := make(map[string]cachedFileResults)
+	for _, rawResult := range rawResults {
+		var result struct {
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+		}
+		if err := json.Unmarshal(rawResult, &result); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SARIF result: %w", err)
+		}
+		if len(result.Locations) == 0 || result.Locations[0].PhysicalLocation.ArtifactLocation.URI == "" {
+			return nil, nil, fmt.Errorf("SARIF result without a file location")
+		}
+
+		file := utils.NormalizeSarifURI(workDirectory, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		if _, ok := expectedFiles[file]; !ok {
+			return nil, nil, fmt.Errorf("SARIF result located in unexpected file %s", file)
+		}
+		fileResults := results[file]
+		fileResults.Results = append(fileResults.Results, rawResult)
+		results[file] = fileResults
+	}
+
+	if artifactsData, ok := run["artifacts"]; ok {
+		var rawArtifacts []json.RawMessage
+		if err := json.Unmarshal(artifactsData, &rawArtifacts); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SARIF artifacts: %w", err)
+		}
+		runArtifacts := []json.RawMessage{}
+		for _, rawArtifact := range rawArtifacts {
+			var artifact utils.Artifact
+			if err := json.Unmarshal(rawArtifact, &artifact); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse SARIF artifact: %w", err)
+			}
+			file := utils.NormalizeSarifURI(workDirectory, artifact.Location.URI)
+			if _, ok := expectedFiles[file]; !ok || artifact.Location.URI == "" {
+				runArtifacts = append(runArtifacts, rawArtifact)
+				continue
+			}
+			fileResults := results[file]
+			fileResults.Artifacts = append(fileResults.Artifacts, rawArtifact)
+			results[file] = fileResults
+		}
+		if run["artifacts"], err = json.Marshal(runArtifacts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return run, results, nil
+}
+
+// writeSarifRun writes a SARIF file with a single run holding the given results and artifacts, sorted by file.
+// The artifacts of the run come first, and the artifact indexes of the locations are updated to the new order.
+func writeSarifRun(sarifFile string, run map[string]json.RawMessage, results map[string]cachedFileResults) error {
+	var artifacts []json.RawMessage
+	if artifactsData, ok := run["artifacts"]; ok {
+		if err := json.Unmarshal(artifactsData, &artifacts); err != nil {
+			return fmt.Errorf("failed to parse SARIF artifacts: %w", err)
+		}
+	}
+	allResults := []json.RawMessage{}
+	for _, file := range sortedKeys(results) {
+		allResults = append(allResults, results[file].Results...)
+		artifacts = append(artifacts, results[file].Artifacts...)
+	}
+
+	runWithResults := make(map[string]interface{}, len(run)+2)
+	for key, value := range run {
+		runWithResults[key] = value
+	}
+	runWithResults["results"] = allResults
+	if artifacts != nil {
+		runWithResults["artifacts"] = artifacts
+	}
+
+	runData, err := reindexArtifactLocations(runWithResults, artifacts)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(utils.SimpleSarifReport{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs:    []json.RawMessage{runData},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sarifFile, data, constants.DefaultFilePerms)
+}
+
+// reindexArtifactLocations marshals a SARIF run, pointing the artifact index of each of its artifact locations
+// to the artifact with the same URI. Indexes of artifacts no longer in the run are removed.
+func reindexArtifactLocations(run map[string]interface{}, artifacts []json.RawMessage) (json.RawMessage, error) {
+	indexes := make(map[string]int, len(artifacts))
+	for i, rawArtifact := range artifacts {
+		var artifact utils.Artifact
+		if err := json.Unmarshal(rawArtifact, &artifact); err != nil {
+			return nil, fmt.Errorf("failed to parse SARIF artifact: %w", err)
+		}
+		if _, ok := indexes[artifact.Location.URI]; !ok {
+			indexes[artifact.Location.URI] = i
+		}
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	var reindex func(value interface{})
+	reindex = func(value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if location, ok := value["artifactLocation"].(map[string]interface{}); ok {
+				if _, ok := location["index"]; ok {
+					uri, _ := location["uri"].(string)
+					if index, ok := indexes[uri]; ok && uri != "" {
+						location["index"] = index
+					} else {
+						delete(location, "index")
+					}
+				}
+			}
+			for _, child := range value {
+				reindex(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				reindex(child)
+			}
+		}
+	}
+	reindex(generic)
+	return json.Marshal(generic)
+}
+
+// markCachedRuns flags the results of the tools that didn't run because every file had cached results
+func markCachedRuns(results []toolRunResult, plans map[string]*toolCachePlan) {
+	for i := range results {
+		if plan := plans[results[i].toolName]; plan != nil && plan.fromCache {
+			results[i].cached = true
+		}
+	}
+}
+
+// sortedKeys returns the keys of a map in a deterministic order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"codacy/cli-v2/cache"
+	"codacy/cli-v2/plugins"
+	"codacy/cli-v2/tools"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRunner reports one result and one artifact per analyzed file, after an artifact of its configuration
+// file, and records the paths it was given
+type recordingRunner struct {
+	mutex sync.Mutex
+	calls [][]string
+	files []string
+}
+
+func (r *recordingRunner) Name() string {
+	return "cache-test-tool"
+}
+
+func (r *recordingRunner) Run(ctx context.Context, req *tools.RunRequest) (*tools.RunResult, error) {
+	r.mutex.Lock()
+	r.calls = append(r.calls, req.PathsToCheck)
+	files := r.files
+	r.mutex.Unlock()
+
+	if len(req.PathsToCheck) > 0 {
+		files = req.PathsToCheck
+	}
+	results := []map[string]interface{}{}
+	artifacts := []map[string]interface{}{{"location": map[string]string{"uri": ".cache-test-tool.json"}}}
+	for i, file := range files {
+		content, err := os.ReadFile(filepath.Join(req.WorkDirectory, file))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, map[string]interface{}{
+			"ruleId":  "content",
+			"message": map[string]string{"text": string(content)},
+			"locations": []map[string]interface{}{
+				{"physicalLocation": map[string]interface{}{"artifactLocation": map[string]interface{}{"uri": file, "index": i + 1}}},
+			},
+		})
+		artifacts = append(artifacts, map[string]interface{}{"location": map[string]string{"uri": file}})
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"version": "2.1.0",
+		"runs": []map[string]interface{}{
+			{"tool": map[string]interface{}{"driver": map[string]string{"name": "cache-test-tool"}}, "results": results, "artifacts": artifacts},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tools.RunResult{ToolName: r.Name(), OutputFile: req.OutputFile}, os.WriteFile(req.OutputFile, data, 0644)
+}
+
+func (r *recordingRunner) takeCalls() [][]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	calls := r.calls
+	r.calls = nil
+	return calls
+}
+
+var registerRecordingRunner sync.Once
+var testRecordingRunner = &recordingRunner{}
+
+func sarifResultMessages(t *testing.T, sarifFile string) map[string]string {
+	data, err := os.ReadFile(sarifFile)
+	require.NoError(t, err)
+
+	var report struct {
+		Runs []struct {
+			Results   []sarifResultForTest `json:"results"`
+			Artifacts []struct {
+				Location struct {
+					URI string `json:"uri"`
+				} `json:"location"`
+			} `json:"artifacts"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &report))
+	require.Len(t, report.Runs, 1)
+	require.Len(t, report.Runs[0].Artifacts, len(report.Runs[0].Results)+1)
+
+	messages := make(map[string]string)
+	for _, result := range report.Runs[0].Results {
+		location := result.Locations[0].PhysicalLocation.ArtifactLocation
+		// Each result points to the artifact of its file
+		require.NotNil(t, location.Index)
+		assert.Equal(t, location.URI, report.Runs[0].Artifacts[*location.Index].Location.URI)
+		messages[location.URI] = result.Message.Text
+	}
+	return messages
+}
+
+type sarifResultForTest struct {
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI   string `json:"uri"`
+				Index *int   `json:"index"`
+			} `json:"artifactLocation"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+}
+
+func TestRunToolWithCacheReusesResultsOfUnchangedFiles(t *testing.T) {
+	registerRecordingRunner.Do(func() { tools.RegisterRunner(testRecordingRunner) })
+	testRecordingRunner.files = []string{"a.js", "b.js"}
+	testRecordingRunner.takeCalls()
+
+	workDirectory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDirectory, "a.js"), []byte("a1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDirectory, "b.js"), []byte("b1"), 0644))
+
+	store := cache.NewStore(filepath.Join(t.TempDir(), "cache"))
+	prepared := &preparedTool{name: "cache-test-tool", tool: &plugins.ToolInfo{Name: "cache-test-tool", Version: "1.0.0"}}
+	outputFile := filepath.Join(t.TempDir(), "output.sarif")
+
+	analyze := func() *toolCachePlan {
+		plan, err := newToolCachePlan(store, prepared, workDirectory, []string{"a.js", "b.js"}, map[string]string{})
+		require.NoError(t, err)
+		require.NoError(t, runToolWithCache(context.Background(), prepared, plan, workDirectory, nil, false, outputFile, io.Discard, io.Discard))
+		return plan
+	}
+
+	// The first run analyzes the original paths and fills the cache
+	plan := analyze()
+	assert.False(t, plan.fromCache)
+	assert.Equal(t, [][]string{nil}, testRecordingRunner.takeCalls())
+	assert.Equal(t, map[string]string{"a.js": "a1", "b.js": "b1"}, sarifResultMessages(t, outputFile))
+
+	// Nothing changed, the tool doesn't run
+	plan = analyze()
+	assert.True(t, plan.fromCache)
+	assert.Empty(t, testRecordingRunner.takeCalls())
+	assert.Equal(t, map[string]string{"a.js": "a1", "b.js": "b1"}, sarifResultMessages(t, outputFile))
+
+	// Only the changed file is analyzed
+	require.NoError(t, os.WriteFile(filepath.Join(workDirectory, "b.js"), []byte("b2"), 0644))
+	plan = analyze()
+	assert.False(t, plan.fromCache)
+	assert.Equal(t, [][]string{{"b.js"}}, testRecordingRunner.takeCalls())
+	assert.Equal(t, map[string]string{"a.js": "a1", "b.js": "b2"}, sarifResultMessages(t, outputFile))
+
+	// The run rebuilt from the cache keeps the results and artifacts of the files of the partial run
+	plan = analyze()
+	assert.True(t, plan.fromCache)
+	assert.Empty(t, testRecordingRunner.takeCalls())
+	assert.Equal(t, map[string]string{"a.js": "a1", "b.js": "b2"}, sarifResultMessages(t, outputFile))
+
+	// A new tool version invalidates the cache
+	prepared.tool.Version = "2.0.0"
+	plan = analyze()
+	assert.False(t, plan.fromCache)
+	assert.Equal(t, [][]string{nil}, testRecordingRunner.takeCalls())
+}
+
+func TestReadSarifRunResultsRejectsUnexpectedFiles(t *testing.T) {
+	sarifFile := filepath.Join(t.TempDir(), "output.sarif")
+	result := `{"locations":[{"physicalLocation":{"artifactLocation":{"uri":"%s"}}}]}`
+	write := func(results string) {
+		require.NoError(t, os.WriteFile(sarifFile, []byte(`{"runs":[{"tool":{},"results":[`+results+`]}]}`), 0644))
+	}
+
+	write(fmt.Sprintf(result, "a.js") + "," + fmt.Sprintf(result, "./a.js"))
+	run, results, err := readSarifRunResults(sarifFile, "/repo", map[string]string{"a.js": "key"})
+	require.NoError(t, err)
+	assert.Len(t, results["a.js"].Results, 2)
+	assert.NotContains(t, run, "results")
+
+	write(fmt.Sprintf(result, "other.js"))
+	_, _, err = readSarifRunResults(sarifFile, "/repo", map[string]string{"a.js": "key"})
+	assert.Error(t, err)
+
+	write(`{"locations":[]}`)
+	_, _, err = readSarifRunResults(sarifFile, "/repo", map[string]string{"a.js": "key"})
+	assert.Error(t, err)
+}
+
+func TestFilesAnalyzedByTool(t *testing.T) {
+	var langConfig LanguagesConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"tools":[
+		{"name":"eslint","extensions":[".js",".ts"]},
+		{"name":"trivy","extensions":[".lock"],"files":["go.mod"]},
+		{"name":"lizard","extensions":[]}
+	]}`), &langConfig))
+
+	files := []string{"src/a.js", "src/b.TS", "README", "go.mod", "yarn.lock", "main.go"}
+
+	assert.Equal(t, []string{"src/a.js", "src/b.TS"}, filesAnalyzedByTool("eslint", files, &langConfig))
+	assert.Equal(t, []string{"go.mod", "yarn.lock"}, filesAnalyzedByTool("trivy", files, &langConfig))
+	assert.Empty(t, filesAnalyzedByTool("lizard", files, &langConfig))
+	assert.Empty(t, filesAnalyzedByTool("pylint", files, &langConfig))
+	assert.Empty(t, filesAnalyzedByTool("eslint", files, nil))
+}
+
+func TestAnalyzesAllPaths(t *testing.T) {
+	plan := &toolCachePlan{
+		run:    map[string]json.RawMessage{},
+		cached: map[string]cachedFileResults{"a.py": {}},
+		missed: []string{"b.py"},
+	}
+
+	assert.False(t, plan.analyzesAllPaths("pylint"))
+	// Duplicated blocks of Lizard may span a changed file and a cached one
+	assert.True(t, plan.analyzesAllPaths("lizard"))
+	assert.True(t, plan.analyzesAllPaths("trivy"))
+}
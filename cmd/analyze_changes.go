@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/utils"
+)
+
+// changedFilesWithinPaths keeps the changed files that are inside at least one of the given paths.
+// When no paths are given, or the current directory is given, all changed files are kept.
+func changedFilesWithinPaths(changedFiles []string, paths []string) []string {
+	if len(paths) == 0 {
+		return changedFiles
+	}
+
+	var result []string
+	for _, file := range changedFiles {
+		for _, path := range paths {
+			cleanPath := filepath.ToSlash(filepath.Clean(path))
+			if cleanPath == "." || file == cleanPath || strings.HasPrefix(file, cleanPath+"/") {
+				result = append(result, file)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// filesSupportedByTools returns, for each tool, the files it supports according to the languages configuration
+func filesSupportedByTools(toolNames []string, files []string) map[string][]string {
+	langConfig, err := LoadLanguageConfig()
+	if err != nil {
+		log.Printf("Warning: Failed to load language configuration: %v. Passing all files to every tool.", err)
+		langConfig = nil
+	}
+
+	result := make(map[string][]string, len(toolNames))
+	for _, toolName := range toolNames {
+		for _, file := range files {
+			if IsToolSupportedForFile(toolName, file, langConfig) {
+				result[toolName] = append(result[toolName], file)
+			}
+		}
+	}
+	return result
+}
+
+// keepResultsOnChangedLines removes the SARIF results that are not located on lines changed according to changedLines.
+// Results without a location are kept, and file level results are kept when the file was changed.
+func keepResultsOnChangedLines(sarifData []byte, baseDir string, changedLines utils.ChangedLines) ([]byte, error) {
+	filteredData, removed, err := utils.FilterSarifResults(sarifData, baseDir, func(issue domain.Issue) bool {
+		if issue.Path == "" {
+			return true
+		}
+		if issue.Region.StartLine <= 0 {
+			return changedLines.ContainsFile(issue.Path)
+		}
+		return changedLines.ContainsLine(issue.Path, issue.Region.StartLine)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Removed %d results outside of the changed lines", removed)
+	return filteredData, nil
+}
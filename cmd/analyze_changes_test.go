@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"codacy/cli-v2/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFilesWithinPaths(t *testing.T) {
+	changedFiles := []string{"README.md", "src/app.js", "src/lib/util.js", "srcfoo/other.js"}
+
+	assert.Equal(t, changedFiles, changedFilesWithinPaths(changedFiles, nil))
+	assert.Equal(t, changedFiles, changedFilesWithinPaths(changedFiles, []string{"."}))
+	assert.Equal(t, []string{"src/app.js", "src/lib/util.js"}, changedFilesWithinPaths(changedFiles, []string{"src"}))
+	assert.Equal(t, []string{"src/lib/util.js"}, changedFilesWithinPaths(changedFiles, []string{"./src/lib/"}))
+	assert.Equal(t, []string{"README.md"}, changedFilesWithinPaths(changedFiles, []string{"README.md"}))
+	assert.Empty(t, changedFilesWithinPaths(changedFiles, []string{"docs"}))
+}
+
+func TestKeepResultsOnChangedLines(t *testing.T) {
+	sarif := `{
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {"name": "ESLint"}},
+			"results": [
+				{"ruleId": "new", "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///repo/src/app.js"}, "region": {"startLine": 4}}}]},
+				{"ruleId": "old", "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///repo/src/app.js"}, "region": {"startLine": 1}}}]},
+				{"ruleId": "other-file", "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/other.js"}, "region": {"startLine": 4}}}]},
+				{"ruleId": "file-level", "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/app.js"}}}]},
+				{"ruleId": "no-location"}
+			]
+		}]
+	}`
+	changedLines := utils.ChangedLines{"src/app.js": {{Start: 4, End: 6}}}
+
+	filtered, err := keepResultsOnChangedLines([]byte(sarif), "/repo", changedLines)
+	require.NoError(t, err)
+
+	var report utils.SarifReport
+	require.NoError(t, json.Unmarshal(filtered, &report))
+	var ruleIDs []string
+	for _, result := range report.Runs[0].Results {
+		ruleIDs = append(ruleIDs, result.RuleID)
+	}
+	assert.Equal(t, []string{"new", "file-level", "no-location"}, ruleIDs)
+}
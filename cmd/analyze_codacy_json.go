@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+)
+
+// codacyJSONOutputFormat writes the issues as Codacy shows them, with the Codacy pattern of every rule
+const codacyJSONOutputFormat = "codacy-json"
+
+// codacyIssues maps the issues to the Codacy patterns of their rules like upload does, so they match the issues
+// Codacy shows, using the patterns set in ruleMappings for rules without a pattern of their own. Suppressed issues,
+// issues without a file and rules without a Codacy pattern are left out.
+func codacyIssues(toolResults []domain.ToolResults, lookupToolPatterns toolPatternsLookup, lookupPattern patternLookup, ruleMappings *userRuleMappings) []CodacyIssue {
+	issues := []CodacyIssue{}
+	lookupPattern = mappedPatternLookup(lookupPattern)
+	unmappedRules := make(map[string]bool)
+	unmapped := 0
+
+	for _, run := range toolResults {
+		// getToolName maps the SARIF driver names to Codacy tool names, like when uploading results
+		toolName := getToolName(strings.ToLower(run.Tool), run.Version)
+		tool, patterns, err := lookupToolPatterns(toolName)
+		if err != nil {
+			log.Printf("Codacy patterns of %s not available: %v", toolName, err)
+		}
+		for _, issue := range run.Issues {
+			if issue.Suppressed || issue.Path == "" {
+				continue
+			}
+			// Patterns of the rule mappings that can't be looked up are reported by mappedPatternLookup
+			_, pattern, _ := mapRuleToPattern(patterns, tool, []string{run.Tool, toolName}, issue.PatternID, ruleMappings, lookupPattern)
+			if pattern == nil {
+				unmapped++
+				patternID := codacyPatternID(tool, issue.PatternID)
+				if !unmappedRules[run.Tool+"/"+patternID] {
+					unmappedRules[run.Tool+"/"+patternID] = true
+					log.Printf("Rule '%s' of %s doesn't have a direct mapping on Codacy", patternID, run.Tool)
+				}
+				continue
+			}
+			issues = append(issues, newCodacyIssue(issue, pattern))
+		}
+	}
+
+	if unmapped > 0 {
+		log.Printf("%d issue(s) left out, as their rules don't have a Codacy pattern", unmapped)
+	}
+	return issues
+}
+
+// writeCodacyJSONOutput writes the Codacy issues as a JSON array to outputFile, or to the console when no file is given
+func writeCodacyJSONOutput(issues []CodacyIssue, outputFile string) error {
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Codacy issues: %w", err)
+	}
+	if outputFile == "" {
+		_, err := fmt.Println(string(data))
+		return err
+	}
+	return os.WriteFile(outputFile, append(data, '\n'), constants.DefaultFilePerms)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodacyIssues(t *testing.T) {
+	lookupToolPatterns := func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		switch toolName {
+		case "ESLint9":
+			return domain.Tool{Name: "ESLint9", Prefix: "ESLint9_"}, []domain.PatternConfiguration{
+				{PatternDefinition: domain.PatternDefinition{Id: "ESLint9_react_jsx-key", Category: "ErrorProne", Level: "Error"}},
+				{PatternDefinition: domain.PatternDefinition{Id: "ESLint9_semi", Category: "CodeStyle", Level: "Info"}},
+			}, nil
+		case "Trivy":
+			return domain.Tool{}, nil, errors.New("offline")
+		}
+		return domain.Tool{}, nil, nil
+	}
+	toolResults := []domain.ToolResults{
+		{Tool: "ESLint", Version: "9.1.0", Issues: []domain.Issue{
+			{PatternID: "react/jsx-key", Path: "src/app.jsx", Region: domain.Region{StartLine: 3}, Message: "Missing key"},
+			{PatternID: "semi", Path: "src/app.jsx", Region: domain.Region{StartLine: 4}, Message: "Missing semicolon", Suppressed: true},
+			{PatternID: "semi", Message: "No file"},
+			{PatternID: "custom/rule", Path: "src/app.jsx", Region: domain.Region{StartLine: 5}, Message: "Unknown rule"},
+		}},
+		{Tool: "Pylint", Issues: []domain.Issue{
+			{PatternID: "C0114", Path: "main.py", Region: domain.Region{StartLine: 1}, Message: "Missing module docstring"},
+		}},
+		{Tool: "Trivy", Issues: []domain.Issue{
+			{PatternID: "vulnerability", Path: "go.mod", Region: domain.Region{StartLine: 1}, Message: "Vulnerable dependency"},
+		}},
+	}
+
+	issues := codacyIssues(toolResults, lookupToolPatterns, nil, nil)
+
+	assert.Equal(t, []CodacyIssue{
+		{Source: "src/app.jsx", Line: 3, Type: "ESLint9_react_jsx-key", Message: "Missing key", Level: "Error", Category: "ErrorProne"},
+	}, issues)
+}
+
+func TestWriteCodacyJSONOutput(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "issues.json")
+
+	require.NoError(t, writeCodacyJSONOutput([]CodacyIssue{}, outputFile))
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(content))
+
+	require.NoError(t, writeCodacyJSONOutput([]CodacyIssue{{Source: "a.js", Line: 1, Type: "ESLint9_semi", Message: "m", Level: "Info", Category: "CodeStyle"}}, outputFile))
+	content, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"source": "a.js", "line": 1, "type": "ESLint9_semi", "message": "m", "level": "Info", "category": "CodeStyle"}]`, string(content))
+}
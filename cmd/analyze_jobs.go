@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// toolRunResult holds the outcome of running a single tool
+type toolRunResult struct {
+	toolName string
+	duration time.Duration
+	err      error
+	// cached is set when the results were taken from the results cache without running the tool
+	cached bool
+}
+
+// toolExecutor runs a prepared tool writing its console output to the given writers
+type toolExecutor func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error
+
+// runToolsConcurrently runs the prepared tools using at most jobs workers at the same time.
+// When more than one job is allowed, the console output of each tool is buffered and written
+// to stdout and stderr only once the tool finishes, so the output of different tools never interleaves.
+// Results are returned in the same order as the prepared tools.
+func runToolsConcurrently(preparedTools []*preparedTool, jobs int, execute toolExecutor, stdout io.Writer, stderr io.Writer) []toolRunResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]toolRunResult, len(preparedTools))
+
+	if jobs == 1 {
+		// Stream the output directly, there is nothing to interleave with
+		for i, prepared := range preparedTools {
+			start := time.Now()
+			err := execute(prepared, stdout, stderr)
+			results[i] = toolRunResult{toolName: prepared.name, duration: time.Since(start), err: err}
+		}
+		return results
+	}
+
+	var consoleMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	workers := make(chan struct{}, jobs)
+
+	for i, prepared := range preparedTools {
+		waitGroup.Add(1)
+		go func(i int, prepared *preparedTool) {
+			defer waitGroup.Done()
+
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			var toolStdout, toolStderr bytes.Buffer
+			start := time.Now()
+			err := execute(prepared, &toolStdout, &toolStderr)
+			results[i] = toolRunResult{toolName: prepared.name, duration: time.Since(start), err: err}
+
+			consoleMutex.Lock()
+			defer consoleMutex.Unlock()
+			stderr.Write(toolStderr.Bytes())
+			stdout.Write(toolStdout.Bytes())
+		}(i, prepared)
+	}
+
+	waitGroup.Wait()
+	return results
+}
+
+// logToolRunSummary logs how each tool run went, including the tools that failed before running
+func logToolRunSummary(results []toolRunResult) {
+	log.Println("Analysis summary:")
+	for _, result := range results {
+		if result.err != nil {
+			log.Printf("  ❌ %s failed after %s: %v", result.toolName, formatDuration(result.duration), result.err)
+		} else if result.cached {
+			log.Printf("  ✅ %s finished in %s (cached)", result.toolName, formatDuration(result.duration))
+		} else {
+			log.Printf("  ✅ %s finished in %s", result.toolName, formatDuration(result.duration))
+		}
+	}
+}
+
+// formatDuration rounds a duration for display purposes
+func formatDuration(duration time.Duration) string {
+	return fmt.Sprint(duration.Round(100 * time.Millisecond))
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func preparedToolsNamed(names ...string) []*preparedTool {
+	prepared := make([]*preparedTool, 0, len(names))
+	for _, name := range names {
+		prepared = append(prepared, &preparedTool{name: name})
+	}
+	return prepared
+}
+
+func TestRunToolsConcurrentlyRespectsJobsLimit(t *testing.T) {
+	var running, maxRunning int32
+
+	execute := func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	results := runToolsConcurrently(preparedToolsNamed("a", "b", "c", "d", "e"), 2, execute, &stdout, &stderr)
+
+	assert.Len(t, results, 5)
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Equal(t, int32(2), maxRunning)
+}
+
+func TestRunToolsConcurrentlyDoesNotInterleaveOutput(t *testing.T) {
+	execute := func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error {
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(stdout, "%s-%d\n", prepared.name, i)
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	runToolsConcurrently(preparedToolsNamed("eslint", "pmd", "trivy"), 3, execute, &stdout, &stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 15)
+	for block := 0; block < 3; block++ {
+		toolName := strings.Split(lines[block*5], "-")[0]
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, fmt.Sprintf("%s-%d", toolName, i), lines[block*5+i], "output of a tool should be contiguous")
+		}
+	}
+}
+
+func TestRunToolsConcurrentlyKeepsResultsOrderAndErrors(t *testing.T) {
+	execute := func(prepared *preparedTool, stdout io.Writer, stderr io.Writer) error {
+		if prepared.name == "pmd" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	for _, jobs := range []int{0, 1, 3} {
+		t.Run(fmt.Sprintf("jobs=%d", jobs), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			results := runToolsConcurrently(preparedToolsNamed("eslint", "pmd", "trivy"), jobs, execute, &stdout, &stderr)
+
+			assert.Equal(t, "eslint", results[0].toolName)
+			assert.NoError(t, results[0].err)
+			assert.Equal(t, "pmd", results[1].toolName)
+			assert.EqualError(t, results[1].err, "boom")
+			assert.Equal(t, "trivy", results[2].toolName)
+			assert.NoError(t, results[2].err)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/formatters"
+	"codacy/cli-v2/tools/lizard"
+	"codacy/cli-v2/utils"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// sarifOutputFormat writes the merged SARIF of every tool
+	sarifOutputFormat = "sarif"
+	// nativeOutputFormat streams the output of each tool in its own format, one tool after another
+	nativeOutputFormat = "native"
+)
+
+// rendersMergedResults tells if the CLI renders the output from the merged SARIF of every tool.
+// Other formats are passed to the tools, which write their own output.
+func rendersMergedResults(format string) bool {
+	if format == sarifOutputFormat || format == codacyJSONOutputFormat {
+		return true
+	}
+	_, ok := formatters.GetFormatter(format)
+	return ok
+}
+
+// outputFormatsDescription lists the formats rendered by the CLI, for the --format flag help
+func outputFormatsDescription() string {
+	formats := append([]string{sarifOutputFormat, codacyJSONOutputFormat}, formatters.RegisteredFormats()...)
+	return fmt.Sprintf("Output format: %s, or %s for the output of each tool", strings.Join(formats, ", "), nativeOutputFormat)
+}
+
+// nativeToolOutputFormat returns the format passed to the tools when they write their own output
+func nativeToolOutputFormat(format string) string {
+	if format == nativeOutputFormat {
+		return ""
+	}
+	return format
+}
+
+// writeSarifOutput writes the merged SARIF to outputFile, or to the console when no file is given
+func writeSarifOutput(sarifData []byte, outputFile string) error {
+	if outputFile == "" {
+		_, err := fmt.Println(string(sarifData))
+		return err
+	}
+	return os.WriteFile(outputFile, sarifData, constants.DefaultFilePerms)
+}
+
+// writeFormattedOutput renders the issues with the formatter of the given format, to outputFile or to the console.
+// Colors are only used on a terminal, and rules are described with the patterns found by lookup, if any.
+func writeFormattedOutput(format string, toolResults []domain.ToolResults, workDirectory string, outputFile string, lookup utils.PatternLookup) error {
+	formatter, ok := formatters.GetFormatter(format)
+	if !ok {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	report := &formatters.Report{
+		ToolResults: toolResults,
+		Colored:     outputFile == "" && !color.NoColor,
+		BaseDir:     workDirectory,
+		Patterns:    patternDescriptions(toolResults, config.Config.ToolsConfigDirectory(), lookup),
+	}
+	// Links are relative to the analyzed directory outside git repositories
+	report.RepositoryPrefix, _ = utils.RepositoryPrefix(workDirectory)
+	if outputFile == "" {
+		return formatter.Format(os.Stdout, report)
+	}
+
+	var buffer bytes.Buffer
+	if err := formatter.Format(&buffer, report); err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, buffer.Bytes(), constants.DefaultFilePerms)
+}
+
+// analyzedFilesByTool lists the files each tool that ran successfully analyzed, so formats like junit can report
+// the files without issues. Tools whose files can't be determined, e.g. outside a git repository, are left out.
+func analyzedFilesByTool(runResults []toolRunResult, workDirectory string, pathsForTool func(toolName string) []string) map[string][]string {
+	repositoryFiles, err := utils.ListRepositoryFiles(workDirectory)
+	if err != nil {
+		log.Printf("Analyzed files not reported: %v", err)
+		return nil
+	}
+	langConfig, err := LoadLanguageConfig()
+	if err != nil {
+		log.Printf("Analyzed files not reported: %v", err)
+		return nil
+	}
+
+	repositoryFileSet := make(map[string]bool, len(repositoryFiles))
+	for _, file := range repositoryFiles {
+		repositoryFileSet[file] = true
+	}
+
+	filesByTool := make(map[string][]string)
+	for _, result := range runResults {
+		if result.err != nil {
+			continue
+		}
+		paths := pathsForTool(result.toolName)
+		if !pathsWithinRepositoryFiles(paths, workDirectory, repositoryFileSet) {
+			continue
+		}
+		if files := filesAnalyzedByTool(result.toolName, changedFilesWithinPaths(repositoryFiles, paths), langConfig); len(files) > 0 {
+			filesByTool[result.toolName] = files
+		}
+	}
+	return filesByTool
+}
+
+// addAnalyzedFiles adds the files analyzed by each tool to its results, matching the tool names with the SARIF
+// driver names. Tools that reported no run at all get results without issues.
+func addAnalyzedFiles(toolResults []domain.ToolResults, filesByTool map[string][]string) []domain.ToolResults {
+	for _, toolName := range sortedKeys(filesByTool) {
+		matched := false
+		for i := range toolResults {
+			if !utils.MatchesToolName(toolResults[i].Tool, toolName) {
+				continue
+			}
+			matched = true
+			toolResults[i].Files = mergeFiles(toolResults[i].Files, filesByTool[toolName])
+		}
+		if !matched {
+			toolResults = append(toolResults, domain.ToolResults{Tool: toolName, Files: filesByTool[toolName]})
+		}
+	}
+	return toolResults
+}
+
+// mergeFiles returns the sorted union of two lists of files
+func mergeFiles(files []string, others []string) []string {
+	set := make(map[string]bool, len(files)+len(others))
+	for _, file := range append(append([]string{}, files...), others...) {
+		set[file] = true
+	}
+	merged := make([]string, 0, len(set))
+	for file := range set {
+		merged = append(merged, file)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// patternDescriptions finds the titles and descriptions of the rules of the issues, in the tools configurations
+// first, or in the Codacy patterns found by lookup, e.g. in the pattern catalog. Each rule is looked up once.
+func patternDescriptions(toolResults []domain.ToolResults, toolsConfigDirectory string, lookup utils.PatternLookup) []formatters.PatternDescription {
+	descriptions := localPatternDescriptions(toolsConfigDirectory)
+	if lookup == nil {
+		return descriptions
+	}
+
+	seen := make(map[string]bool)
+	for _, run := range toolResults {
+		for _, issue := range run.Issues {
+			key := run.Tool + "\x00" + issue.PatternID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			pattern, ok := lookup(run.Tool, run.Version, issue.PatternID)
+			if !ok || (pattern.Title == "" && pattern.Description == "") {
+				continue
+			}
+			descriptions = append(descriptions, formatters.PatternDescription{
+				Tool:        run.Tool,
+				ID:          issue.PatternID,
+				Title:       pattern.Title,
+				Description: pattern.Description,
+			})
+		}
+	}
+	return descriptions
+}
+
+// localPatternDescriptions reads the titles and descriptions of the patterns stored in the tools configurations.
+// Only the Lizard configuration keeps them, as the other tools use their own configuration formats.
+func localPatternDescriptions(toolsConfigDirectory string) []formatters.PatternDescription {
+	configFile := filepath.Join(toolsConfigDirectory, "lizard.yaml")
+	if _, err := os.Stat(configFile); err != nil {
+		return nil
+	}
+	patterns, err := lizard.ReadConfig(configFile)
+	if err != nil {
+		log.Printf("Pattern descriptions of lizard not available: %v", err)
+		return nil
+	}
+
+	descriptions := make([]formatters.PatternDescription, 0, len(patterns))
+	for _, pattern := range patterns {
+		descriptions = append(descriptions, formatters.PatternDescription{
+			Tool:        "lizard",
+			ID:          pattern.Id,
+			Title:       pattern.Title,
+			Description: pattern.Description,
+		})
+	}
+	return descriptions
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/formatters"
+	"codacy/cli-v2/tools/lizard"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRendersMergedResults(t *testing.T) {
+	assert.True(t, rendersMergedResults("sarif"))
+	assert.True(t, rendersMergedResults("codacy-json"))
+	assert.True(t, rendersMergedResults("text"))
+	assert.False(t, rendersMergedResults("native"))
+	assert.False(t, rendersMergedResults("json"), "other formats are passed to the tools")
+
+	assert.Equal(t, "", nativeToolOutputFormat("native"))
+	assert.Equal(t, "json", nativeToolOutputFormat("json"))
+}
+
+func TestWriteFormattedOutputToFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "results.txt")
+	toolResults := []domain.ToolResults{{
+		Tool:   "ESLint",
+		Issues: []domain.Issue{{Tool: "ESLint", PatternID: "semi", Path: "a.js", Region: domain.Region{StartLine: 1}, Level: "error", Message: "Missing semicolon."}},
+	}}
+
+	require.NoError(t, writeFormattedOutput("text", toolResults, t.TempDir(), outputFile, nil))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Missing semicolon.")
+	assert.NotContains(t, string(data), "\x1b[", "files are written without colors")
+
+	assert.Error(t, writeFormattedOutput("unknown", toolResults, t.TempDir(), outputFile, nil))
+}
+
+// renderMergedResults renders a merged SARIF in a format like analyze does, enriching it with the given patterns
+func renderMergedResults(t *testing.T, sarif string, format string, lookupToolPatterns toolPatternsLookup) []byte {
+	workDirectory := t.TempDir()
+	outputFile := filepath.Join(t.TempDir(), "results")
+
+	lookup := codacyPatternLookup(lookupToolPatterns)
+	_, toolResults, err := processMergedResults([]byte(sarif), workDirectory, false, true, lookup)
+	require.NoError(t, err)
+	require.NoError(t, writeFormattedOutput(format, toolResults, workDirectory, outputFile, lookup))
+
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	return data
+}
+
+func TestGitlabOutputUsesCodacySeverities(t *testing.T) {
+	var report []struct {
+		CheckName string `json:"check_name"`
+		Severity  string `json:"severity"`
+	}
+	require.NoError(t, json.Unmarshal(renderMergedResults(t, trivySarif, "gitlab", trivyPatternsLookup), &report))
+
+	require.Len(t, report, 1)
+	// The result is a note, but its pattern is a critical security issue
+	assert.Equal(t, "blocker", report[0].Severity)
+}
+
+func TestCheckstyleOutputUsesCodacySeverities(t *testing.T) {
+	var report struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Severity string `xml:"severity,attr"`
+				Source   string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	require.NoError(t, xml.Unmarshal(renderMergedResults(t, trivySarif, "checkstyle", trivyPatternsLookup), &report))
+
+	require.Len(t, report.Files, 1)
+	require.Len(t, report.Files[0].Errors, 1)
+	// The result is a note, but its pattern is critical
+	assert.Equal(t, "error", report.Files[0].Errors[0].Severity)
+	assert.Equal(t, "Trivy.CVE-2024-0001", report.Files[0].Errors[0].Source)
+}
+
+func TestAddAnalyzedFiles(t *testing.T) {
+	toolResults := []domain.ToolResults{
+		{Tool: "ESLint", Files: []string{"b.js"}, Issues: []domain.Issue{{Tool: "ESLint", PatternID: "semi", Path: "b.js"}}},
+		{Tool: "Opengrep OSS"},
+	}
+
+	toolResults = addAnalyzedFiles(toolResults, map[string][]string{
+		"eslint":   {"a.js", "b.js"},
+		"opengrep": {"a.js"},
+		"trivy":    {"go.mod"},
+	})
+
+	require.Len(t, toolResults, 3)
+	assert.Equal(t, []string{"a.js", "b.js"}, toolResults[0].Files)
+	assert.Len(t, toolResults[0].Issues, 1)
+	assert.Equal(t, []string{"a.js"}, toolResults[1].Files)
+	assert.Equal(t, domain.ToolResults{Tool: "trivy", Files: []string{"go.mod"}}, toolResults[2], "tools without a run are still reported")
+}
+
+func TestLocalPatternDescriptions(t *testing.T) {
+	toolsConfigDirectory := t.TempDir()
+	assert.Empty(t, localPatternDescriptions(toolsConfigDirectory))
+
+	require.NoError(t, lizard.CreateLizardConfig(toolsConfigDirectory, []domain.PatternConfiguration{{
+		PatternDefinition: domain.PatternDefinition{
+			Id:          "Lizard_nloc-medium",
+			Title:       "Method too long",
+			Description: "Long methods are hard to understand",
+			Parameters:  []domain.ParameterConfiguration{{Name: "threshold", Default: "50"}},
+		},
+	}}))
+
+	assert.Equal(t, []formatters.PatternDescription{{
+		Tool:        "lizard",
+		ID:          "Lizard_nloc-medium",
+		Title:       "Method too long",
+		Description: "Long methods are hard to understand",
+	}}, localPatternDescriptions(toolsConfigDirectory))
+}
+
+func TestPatternDescriptionsFromCatalog(t *testing.T) {
+	toolResults := []domain.ToolResults{{Tool: "Trivy", Version: "0.59.1", Issues: []domain.Issue{
+		{Tool: "Trivy", PatternID: "CVE-2024-0001"},
+		{Tool: "Trivy", PatternID: "CVE-2024-0001"},
+		{Tool: "Trivy", PatternID: "CVE-2024-9999"},
+	}}}
+	lookups := 0
+	lookup := func(driverName string, driverVersion string, ruleID string) (domain.PatternDefinition, bool) {
+		lookups++
+		return codacyPatternLookup(trivyPatternsLookup)(driverName, driverVersion, ruleID)
+	}
+
+	assert.Equal(t, []formatters.PatternDescription{{
+		Tool:        "Trivy",
+		ID:          "CVE-2024-0001",
+		Title:       "Dependency with a known CVE",
+		Description: "A dependency has a known vulnerability",
+	}}, patternDescriptions(toolResults, t.TempDir(), lookup))
+	assert.Equal(t, 2, lookups, "each rule is looked up once")
+}
+
+func TestHTMLOutputDescribesRulesAndCategories(t *testing.T) {
+	html := string(renderMergedResults(t, trivySarif, "html", trivyPatternsLookup))
+
+	assert.Contains(t, html, "Dependency with a known CVE")
+	assert.Contains(t, html, "A dependency has a known vulnerability")
+	assert.Contains(t, html, "Security")
+	assert.NotContains(t, html, "Uncategorized")
+}
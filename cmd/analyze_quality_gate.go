@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+
+	"github.com/spf13/cobra"
+)
+
+var failOnLevel string
+var maxIssues int
+var failOnToolError bool
+
+// sarifLevelRanks orders SARIF levels by severity
+var sarifLevelRanks = map[string]int{
+	"none":    0,
+	"note":    1,
+	"warning": 2,
+	"error":   3,
+}
+
+// sarifLevelRank returns the severity rank of a SARIF level. Results without a level are warnings, as per the SARIF spec.
+func sarifLevelRank(level string) int {
+	if level == "" {
+		level = "warning"
+	}
+	return sarifLevelRanks[level]
+}
+
+// resolveQualityGate combines the quality gate of codacy.yaml with the command line flags, which take precedence
+func resolveQualityGate(cmd *cobra.Command) (config.QualityGate, error) {
+	gate := config.Config.QualityGate()
+
+	if cmd.Flags().Changed("fail-on") {
+		gate.FailOn = failOnLevel
+	}
+	if cmd.Flags().Changed("max-issues") {
+		if maxIssues < 0 {
+			return gate, fmt.Errorf("invalid --max-issues %d, expected a non-negative number", maxIssues)
+		}
+		limit := maxIssues
+		gate.MaxIssues = &limit
+	}
+	if cmd.Flags().Changed("fail-on-tool-error") {
+		gate.FailOnToolError = failOnToolError
+	}
+
+	if err := config.ValidateFailOnLevel(gate.FailOn); err != nil {
+		return gate, err
+	}
+	return gate, nil
+}
+
+// hasIssueThresholds reports whether the quality gate checks the issues found
+func hasIssueThresholds(gate config.QualityGate) bool {
+	return gate.FailOn != "" || gate.MaxIssues != nil
+}
+
+// countIssuesForQualityGate counts the issues at or above the fail_on level, or all of them when it is not set
+func countIssuesForQualityGate(toolResults []domain.ToolResults, failOn string) int {
+	minimumRank := 0
+	if failOn != "" {
+		minimumRank = sarifLevelRank(failOn)
+	}
+
+	count := 0
+	for _, results := range toolResults {
+		for _, issue := range results.Issues {
+			if !issue.Suppressed && sarifLevelRank(issue.Level) >= minimumRank {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// evaluateQualityGate returns the exit code analyze should finish with and the reason why the gate failed.
+// toolResults may be nil when the results were not collected, in which case only tool errors are checked.
+// A tool error takes precedence over the issue thresholds, as the results are incomplete.
+func evaluateQualityGate(gate config.QualityGate, toolResults []domain.ToolResults, runResults []toolRunResult) (int, string) {
+	if gate.FailOnToolError {
+		var failedTools []string
+		for _, result := range runResults {
+			if result.err != nil {
+				failedTools = append(failedTools, result.toolName)
+			}
+		}
+		if len(failedTools) > 0 {
+			return constants.ExitCodeToolError, fmt.Sprintf("%d tool(s) failed to run: %v", len(failedTools), failedTools)
+		}
+	}
+
+	if toolResults == nil || !hasIssueThresholds(gate) {
+		return 0, ""
+	}
+
+	count := countIssuesForQualityGate(toolResults, gate.FailOn)
+
+	allowed := 0
+	if gate.MaxIssues != nil {
+		allowed = *gate.MaxIssues
+	}
+	if count > allowed {
+		level := "any level"
+		if gate.FailOn != "" {
+			level = fmt.Sprintf("level %s or higher", gate.FailOn)
+		}
+		return constants.ExitCodeQualityGateFailed, fmt.Sprintf("found %d issue(s) of %s, the maximum allowed is %d", count, level, allowed)
+	}
+
+	return 0, ""
+}
+
+// enforceQualityGate exits with the quality gate exit code when the gate fails
+func enforceQualityGate(gate config.QualityGate, toolResults []domain.ToolResults, runResults []toolRunResult) {
+	exitCode, reason := evaluateQualityGate(gate, toolResults, runResults)
+	if exitCode != 0 {
+		log.Printf("❌ Quality gate failed: %s", reason)
+		exitFunc(exitCode)
+	}
+}
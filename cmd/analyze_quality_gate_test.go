@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var qualityGateResults = []domain.ToolResults{
+	{
+		Tool: "ESLint",
+		Issues: []domain.Issue{
+			{PatternID: "a", Level: "error"},
+			{PatternID: "b", Level: "warning"},
+			{PatternID: "c", Level: "warning"},
+			{PatternID: "d", Level: "note"},
+		},
+	},
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestEvaluateQualityGate(t *testing.T) {
+	tests := []struct {
+		name         string
+		gate         config.QualityGate
+		runResults   []toolRunResult
+		expectedCode int
+	}{
+		{name: "no gate", gate: config.QualityGate{}, expectedCode: 0},
+		{name: "fail on error", gate: config.QualityGate{FailOn: "error"}, expectedCode: constants.ExitCodeQualityGateFailed},
+		{name: "fail on warning within limit", gate: config.QualityGate{FailOn: "warning", MaxIssues: intPointer(3)}, expectedCode: 0},
+		{name: "fail on note over limit", gate: config.QualityGate{FailOn: "note", MaxIssues: intPointer(3)}, expectedCode: constants.ExitCodeQualityGateFailed},
+		{name: "max issues only", gate: config.QualityGate{MaxIssues: intPointer(4)}, expectedCode: 0},
+		{name: "max issues only exceeded", gate: config.QualityGate{MaxIssues: intPointer(2)}, expectedCode: constants.ExitCodeQualityGateFailed},
+		{
+			name:         "tool error ignored",
+			gate:         config.QualityGate{},
+			runResults:   []toolRunResult{{toolName: "pmd", err: errors.New("crash")}},
+			expectedCode: 0,
+		},
+		{
+			name:         "tool error takes precedence",
+			gate:         config.QualityGate{FailOn: "error", FailOnToolError: true},
+			runResults:   []toolRunResult{{toolName: "eslint"}, {toolName: "pmd", err: errors.New("crash")}},
+			expectedCode: constants.ExitCodeToolError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode, reason := evaluateQualityGate(tt.gate, qualityGateResults, tt.runResults)
+			assert.Equal(t, tt.expectedCode, exitCode)
+			if tt.expectedCode != 0 {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateQualityGateWithoutResultsOnlyChecksToolErrors(t *testing.T) {
+	gate := config.QualityGate{FailOn: "note", FailOnToolError: true}
+
+	exitCode, _ := evaluateQualityGate(gate, nil, []toolRunResult{{toolName: "eslint"}})
+	assert.Equal(t, 0, exitCode)
+
+	exitCode, _ = evaluateQualityGate(gate, nil, []toolRunResult{{toolName: "eslint", err: errors.New("crash")}})
+	assert.Equal(t, constants.ExitCodeToolError, exitCode)
+}
+
+func TestResolveQualityGateFlagsOverrideConfig(t *testing.T) {
+	original := config.Config.QualityGate()
+	defer config.Config.SetQualityGate(original)
+	config.Config.SetQualityGate(config.QualityGate{FailOn: "error", MaxIssues: intPointer(10), FailOnToolError: true})
+
+	newCommand := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&failOnLevel, "fail-on", "", "")
+		cmd.Flags().IntVar(&maxIssues, "max-issues", 0, "")
+		cmd.Flags().BoolVar(&failOnToolError, "fail-on-tool-error", false, "")
+		return cmd
+	}
+
+	cmd := newCommand()
+	gate, err := resolveQualityGate(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "error", gate.FailOn)
+	assert.Equal(t, 10, *gate.MaxIssues)
+	assert.True(t, gate.FailOnToolError)
+
+	cmd = newCommand()
+	require.NoError(t, cmd.Flags().Parse([]string{"--fail-on", "warning", "--max-issues", "0", "--fail-on-tool-error=false"}))
+	gate, err = resolveQualityGate(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "warning", gate.FailOn)
+	assert.Equal(t, 0, *gate.MaxIssues)
+	assert.False(t, gate.FailOnToolError)
+
+	cmd = newCommand()
+	require.NoError(t, cmd.Flags().Parse([]string{"--fail-on", "critical"}))
+	_, err = resolveQualityGate(cmd)
+	assert.Error(t, err)
+}
+
+func TestCountIssuesForQualityGateIgnoresSuppressedIssues(t *testing.T) {
+	toolResults := []domain.ToolResults{{
+		Tool: "ESLint",
+		Issues: []domain.Issue{
+			{PatternID: "a", Level: "error"},
+			{PatternID: "b", Level: "error", Suppressed: true},
+		},
+	}}
+
+	assert.Equal(t, 1, countIssuesForQualityGate(toolResults, "error"))
+	assert.Equal(t, 1, countIssuesForQualityGate(toolResults, ""))
+}
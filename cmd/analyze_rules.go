@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/utils"
+)
+
+// processMergedResults enriches the rules of the merged SARIF with the Codacy patterns, so every output format
+// gets the Codacy category and severity of the results, or sets the rules to null with --null-rules. The issues
+// are only parsed when parseIssues is set, as the SARIF output doesn't need them unless the quality gate does.
+func processMergedResults(sarifData []byte, workDirectory string, setNullRules bool, parseIssues bool, lookup utils.PatternLookup) ([]byte, []domain.ToolResults, error) {
+	var err error
+	if setNullRules {
+		if sarifData, err = utils.FilterRulesFromSarif(sarifData); err != nil {
+			return nil, nil, fmt.Errorf("failed to filter rules from SARIF: %w", err)
+		}
+	} else if sarifData, err = utils.EnrichSarifRules(sarifData, lookup); err != nil {
+		return nil, nil, fmt.Errorf("failed to enrich rules in SARIF: %w", err)
+	}
+
+	if !parseIssues {
+		return sarifData, nil, nil
+	}
+	toolResults, err := utils.ParseSarifIssues(sarifData, workDirectory)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse analysis results: %w", err)
+	}
+	return sarifData, toolResults, nil
+}
+
+// codacyPatternLookup finds the Codacy patterns of the rules reported by the tools, looking up the patterns of
+// each tool once per analysis, e.g. in the pattern catalog. Rules are left as reported by the tools when the
+// patterns can't be fetched, e.g. when offline, and no other tool is looked up after such a failure.
+func codacyPatternLookup(lookupToolPatterns toolPatternsLookup) utils.PatternLookup {
+	patternsByTool := make(map[string]*toolPatterns)
+	failed := false
+
+	return func(driverName string, driverVersion string, ruleID string) (domain.PatternDefinition, bool) {
+		// getToolName maps the SARIF driver names to Codacy tool names, like when uploading results
+		toolName := getToolName(strings.ToLower(driverName), driverVersion)
+
+		patterns, ok := patternsByTool[toolName]
+		if !ok && !failed {
+			tool, configurations, err := lookupToolPatterns(toolName)
+			if err != nil {
+				log.Printf("Rules not enriched with Codacy patterns: %v", err)
+				failed = true
+			} else if tool.Name != "" {
+				patterns = newToolPatterns(tool, configurations)
+			}
+			patternsByTool[toolName] = patterns
+		}
+		return patterns.find(ruleID)
+	}
+}
+
+// toolPatterns indexes the patterns of a Codacy tool by the rule ids reported by the tool
+type toolPatterns struct {
+	byRuleID map[string]domain.PatternDefinition
+}
+
+// newToolPatterns indexes patterns by their id without the tool prefix, as Codacy pattern ids are the
+// prefixed rule ids with slashes replaced by underscores, e.g. ESLint8_react_jsx-key for react/jsx-key
+func newToolPatterns(tool domain.Tool, configurations []domain.PatternConfiguration) *toolPatterns {
+	patterns := &toolPatterns{byRuleID: make(map[string]domain.PatternDefinition, len(configurations))}
+	for _, configuration := range configurations {
+		definition := configuration.PatternDefinition
+		ruleID := strings.ToLower(strings.TrimPrefix(definition.Id, tool.Prefix))
+		patterns.byRuleID[ruleID] = definition
+	}
+	return patterns
+}
+
+// find returns the pattern of a rule. Rules that are only the last part of the pattern id, e.g. PMD rules
+// without their category, match when a single pattern ends with the rule id.
+func (p *toolPatterns) find(ruleID string) (domain.PatternDefinition, bool) {
+	if p == nil || ruleID == "" {
+		return domain.PatternDefinition{}, false
+	}
+	key := strings.ToLower(strings.ReplaceAll(ruleID, "/", "_"))
+	if pattern, ok := p.byRuleID[key]; ok {
+		return pattern, true
+	}
+
+	var match domain.PatternDefinition
+	matches := 0
+	for id, pattern := range p.byRuleID {
+		if strings.HasSuffix(id, "_"+key) {
+			match = pattern
+			matches++
+		}
+	}
+	return match, matches == 1
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternLookup(t *testing.T) {
+	lookups := make(map[string]int)
+	lookup := codacyPatternLookup(func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		lookups[toolName]++
+		switch toolName {
+		case "ESLint":
+			return domain.Tool{Uuid: "eslint-uuid", Name: "ESLint", Prefix: "ESLint8_"}, []domain.PatternConfiguration{
+				{PatternDefinition: domain.PatternDefinition{Id: "ESLint8_no-var", Title: "Disallow var"}},
+				{PatternDefinition: domain.PatternDefinition{Id: "ESLint8_react_jsx-key", Title: "Require keys"}},
+			}, nil
+		case "PMD7":
+			return domain.Tool{Uuid: "pmd-uuid", Name: "PMD7", Prefix: "PMD7_"}, []domain.PatternConfiguration{
+				{PatternDefinition: domain.PatternDefinition{Id: "PMD7_category_java_bestpractices_UnusedPrivateField", Title: "Unused private field"}},
+				{PatternDefinition: domain.PatternDefinition{Id: "PMD7_category_java_design_Unused", Title: "Ambiguous 1"}},
+				{PatternDefinition: domain.PatternDefinition{Id: "PMD7_category_java_errorprone_Unused", Title: "Ambiguous 2"}},
+			}, nil
+		}
+		return domain.Tool{}, nil, nil
+	})
+
+	pattern, ok := lookup("ESLint", "8.57.0", "no-var")
+	assert.True(t, ok)
+	assert.Equal(t, "Disallow var", pattern.Title)
+
+	pattern, ok = lookup("ESLint", "8.57.0", "react/jsx-key")
+	assert.True(t, ok)
+	assert.Equal(t, "Require keys", pattern.Title)
+
+	pattern, ok = lookup("PMD", "7.11.0", "UnusedPrivateField")
+	assert.True(t, ok, "rules without their category match the end of the pattern id")
+	assert.Equal(t, "Unused private field", pattern.Title)
+
+	_, ok = lookup("PMD", "7.11.0", "Unused")
+	assert.False(t, ok, "ambiguous rules don't match")
+
+	_, ok = lookup("ESLint", "8.57.0", "unknown-rule")
+	assert.False(t, ok)
+
+	_, ok = lookup("Trivy", "0.59.1", "CVE-2024-0001")
+	assert.False(t, ok, "tools unknown to Codacy have no patterns")
+	_, ok = lookup("Trivy", "0.59.1", "CVE-2024-0002")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]int{"ESLint": 1, "PMD7": 1, "Trivy": 1}, lookups, "the patterns of each tool are looked up once")
+}
+
+func TestPatternLookupOffline(t *testing.T) {
+	lookups := 0
+	lookup := codacyPatternLookup(func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		lookups++
+		return domain.Tool{}, nil, errors.New("network unreachable")
+	})
+
+	_, ok := lookup("ESLint", "8.57.0", "no-var")
+	assert.False(t, ok)
+	_, ok = lookup("PMD", "7.11.0", "UnusedPrivateField")
+	assert.False(t, ok)
+	assert.Equal(t, 1, lookups, "no tool is looked up after a failure")
+}
+
+func TestPatternLookupFromCatalog(t *testing.T) {
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(filepath.Join(t.TempDir(), "catalog.json"), &toolFetches, patternFetches)
+
+	pattern, ok := codacyPatternLookup(store.toolPatterns)("Trivy", "0.59.1", "rule")
+
+	require.True(t, ok)
+	assert.Equal(t, "Checks the rule", pattern.Description)
+	assert.Equal(t, "High", pattern.SeverityLevel)
+	assert.Equal(t, 5, pattern.TimeToFix)
+}
+
+// trivySarif has a Trivy result whose level is lower than the severity of its Codacy pattern
+const trivySarif = `{
+	"version": "2.1.0",
+	"runs": [{
+		"tool": {"driver": {"name": "Trivy", "version": "0.59.1"}},
+		"results": [{
+			"ruleId": "CVE-2024-0001",
+			"level": "note",
+			"message": {"text": "Vulnerable dependency"},
+			"locations": [{"physicalLocation": {"artifactLocation": {"uri": "go.mod"}, "region": {"startLine": 3}}}]
+		}]
+	}]
+}`
+
+// trivyPatternsLookup looks up the Codacy pattern of the Trivy result of trivySarif
+func trivyPatternsLookup(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+	if toolName != "Trivy" {
+		return domain.Tool{}, nil, nil
+	}
+	return domain.Tool{Name: "Trivy", Prefix: "Trivy_"}, []domain.PatternConfiguration{
+		{PatternDefinition: domain.PatternDefinition{
+			Id: "Trivy_CVE-2024-0001", Level: "Error", Category: "Security", SeverityLevel: "Critical",
+			Title: "Dependency with a known CVE", Description: "A dependency has a known vulnerability",
+		}},
+	}, nil
+}
+
+func TestProcessMergedResultsEnrichesEveryFormat(t *testing.T) {
+	sarifData, toolResults, err := processMergedResults([]byte(trivySarif), t.TempDir(), false, true, codacyPatternLookup(trivyPatternsLookup))
+
+	require.NoError(t, err)
+	assert.Contains(t, string(sarifData), `"title": "Dependency with a known CVE"`)
+	require.Len(t, toolResults, 1)
+	require.Len(t, toolResults[0].Issues, 1)
+	assert.Equal(t, "Security", toolResults[0].Issues[0].Category)
+	assert.Equal(t, "Critical", toolResults[0].Issues[0].Severity)
+
+	_, toolResults, err = processMergedResults([]byte(trivySarif), t.TempDir(), false, false, codacyPatternLookup(trivyPatternsLookup))
+	require.NoError(t, err)
+	assert.Nil(t, toolResults, "issues are only parsed when needed")
+}
+
+func TestProcessMergedResultsWithNullRules(t *testing.T) {
+	lookups := 0
+	lookup := codacyPatternLookup(func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		lookups++
+		return trivyPatternsLookup(toolName)
+	})
+
+	sarifData, toolResults, err := processMergedResults([]byte(trivySarif), t.TempDir(), true, true, lookup)
+
+	require.NoError(t, err)
+	assert.Contains(t, string(sarifData), `"rules": null`)
+	require.Len(t, toolResults, 1)
+	assert.Empty(t, toolResults[0].Issues[0].Severity)
+	assert.Zero(t, lookups)
+}
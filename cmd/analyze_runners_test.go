@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"codacy/cli-v2/plugins"
+	"codacy/cli-v2/tools"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEverySupportedToolHasARunner(t *testing.T) {
+	supportedTools, err := plugins.GetSupportedTools()
+	require.NoError(t, err)
+
+	for toolName := range supportedTools {
+		_, ok := tools.GetRunner(toolName)
+		assert.True(t, ok, "tool %s has a plugin but no registered runner", toolName)
+	}
+}
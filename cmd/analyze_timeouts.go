@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+)
+
+var toolTimeoutFlags []string
+
+// resolveToolTimeouts combines the tool_timeouts section of codacy.yaml with the --tool-timeout flags.
+// A flag without a tool name replaces every configured timeout, while "tool=duration" flags only
+// replace the timeout of that tool.
+func resolveToolTimeouts(configured config.ToolTimeouts, flags []string) (config.ToolTimeouts, error) {
+	timeouts := make(config.ToolTimeouts, len(configured))
+	for toolName, timeout := range configured {
+		timeouts[toolName] = timeout
+	}
+
+	for _, flag := range flags {
+		if strings.Contains(flag, "=") {
+			continue
+		}
+		timeout, err := config.ParseToolTimeout(flag)
+		if err != nil {
+			return nil, err
+		}
+		timeouts = config.ToolTimeouts{config.DefaultToolTimeoutKey: timeout}
+	}
+
+	for _, flag := range flags {
+		toolName, value, found := strings.Cut(flag, "=")
+		if !found {
+			continue
+		}
+		if toolName == "" {
+			return nil, fmt.Errorf("invalid tool timeout %q, expected <tool>=<duration>", flag)
+		}
+		timeout, err := config.ParseToolTimeout(value)
+		if err != nil {
+			return nil, err
+		}
+		timeouts[toolName] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// analysisContext returns a context that is cancelled on SIGINT or SIGTERM, so running tools are killed
+func analysisContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// toolRunContextError explains why a tool run was stopped by its context, or returns nil when it wasn't
+func toolRunContextError(ctx context.Context, prepared *preparedTool) error {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("timed out after %s", prepared.timeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return errors.New("interrupted")
+	}
+	return nil
+}
+
+// exitIfInterrupted stops the analysis when it was interrupted by a signal, after the run summary was logged
+func exitIfInterrupted(ctx context.Context) {
+	if ctx.Err() != nil {
+		log.Println("Analysis interrupted")
+		exitFunc(constants.ExitCodeInterrupted)
+	}
+}
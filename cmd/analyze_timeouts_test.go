@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/tools"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveToolTimeouts(t *testing.T) {
+	configured := config.ToolTimeouts{"default": 10 * time.Minute, "pmd": 30 * time.Minute}
+
+	tests := []struct {
+		name     string
+		flags    []string
+		expected config.ToolTimeouts
+	}{
+		{
+			name:     "configuration only",
+			expected: configured,
+		},
+		{
+			name:     "tool flag overrides its timeout",
+			flags:    []string{"pmd=1h"},
+			expected: config.ToolTimeouts{"default": 10 * time.Minute, "pmd": time.Hour},
+		},
+		{
+			name:     "global flag replaces the configuration",
+			flags:    []string{"pmd=1h", "5m"},
+			expected: config.ToolTimeouts{"default": 5 * time.Minute, "pmd": time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeouts, err := resolveToolTimeouts(configured, tt.flags)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, timeouts)
+		})
+	}
+
+	assert.Equal(t, 5*time.Minute, config.ToolTimeouts{"default": 5 * time.Minute}.For("eslint"))
+	assert.Equal(t, time.Duration(0), config.ToolTimeouts{}.For("eslint"))
+
+	for _, invalid := range []string{"soon", "-5m", "0s", "=5m", "pmd=never"} {
+		_, err := resolveToolTimeouts(nil, []string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+// blockingRunner waits until its context is done and, like runners ignoring exit codes, reports no error
+type blockingRunner struct{}
+
+func (blockingRunner) Name() string {
+	return "timeout-test-tool"
+}
+
+func (blockingRunner) Run(ctx context.Context, req *tools.RunRequest) (*tools.RunResult, error) {
+	<-ctx.Done()
+	return &tools.RunResult{ToolName: "timeout-test-tool", ExitCode: -1}, nil
+}
+
+func init() {
+	tools.RegisterRunner(blockingRunner{})
+}
+
+func TestRunToolByNameReportsTimeoutsAndInterruptions(t *testing.T) {
+	prepared := &preparedTool{name: "timeout-test-tool", timeout: 20 * time.Millisecond}
+
+	err := runToolByName(context.Background(), prepared, t.TempDir(), nil, false, "", "", io.Discard, io.Discard)
+	assert.EqualError(t, err, "timed out after 20ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	prepared.timeout = 0
+	err = runToolByName(ctx, prepared, t.TempDir(), nil, false, "", "", io.Discard, io.Discard)
+	assert.EqualError(t, err, "interrupted")
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	codacyclient "codacy/cli-v2/codacy-client"
+	"codacy/cli-v2/config"
+)
+
+// apiURLEnvVar is the environment variable with the base URL of the Codacy API
+const apiURLEnvVar = "CODACY_API_BASE_URL"
+
+// codacyCloudUploadAPIBase is the host of the Codacy cloud API receiving analysis results
+const codacyCloudUploadAPIBase = "https://api.codacy.com"
+
+var apiURLFlag string
+
+// applyAPIURL points every call to the Codacy API to the base URL given by the --api-url flag, the
+// CODACY_API_BASE_URL environment variable or the api_url setting of cli-config.yaml, in this order
+func applyAPIURL() error {
+	configured, err := config.Config.GetCliAPIURL()
+	if err != nil {
+		log.Printf("Warning: api_url of cli-config.yaml ignored: %v", err)
+	}
+
+	apiURL, persist, err := resolveAPIURL(apiURLFlag, os.Getenv(apiURLEnvVar), configured)
+	if err != nil {
+		return err
+	}
+	codacyclient.CodacyApiBase = apiURL
+	if persist {
+		// Kept in cli-config.yaml when init or config reset rewrite it
+		config.Config.SetAPIURL(apiURL)
+	}
+	return nil
+}
+
+// resolveAPIURL returns the base URL of the Codacy API, and whether it should be kept in cli-config.yaml.
+// The URL of the environment variable is not kept, as it only applies to the current environment.
+func resolveAPIURL(flagValue string, envValue string, configValue string) (string, bool, error) {
+	for _, source := range []struct {
+		name    string
+		value   string
+		persist bool
+	}{
+		{"--api-url", flagValue, true},
+		{apiURLEnvVar, envValue, false},
+		{"api_url of cli-config.yaml", configValue, true},
+	} {
+		if strings.TrimSpace(source.value) == "" {
+			continue
+		}
+		apiURL, err := normalizeAPIURL(source.value)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid %s: %w", source.name, err)
+		}
+		return apiURL, source.persist, nil
+	}
+	return codacyclient.DefaultCodacyApiBase, false, nil
+}
+
+// normalizeAPIURL checks that an API URL is an absolute http(s) URL and removes its trailing slashes
+func normalizeAPIURL(value string) (string, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "/")
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%q is not an http or https URL", value)
+	}
+	return value, nil
+}
+
+// uploadAPIBase is the base URL of the API receiving analysis results. The Codacy cloud receives them on its
+// own host, while self-hosted installations receive them on the configured API URL.
+func uploadAPIBase() string {
+	if codacyclient.CodacyApiBase == codacyclient.DefaultCodacyApiBase {
+		return codacyCloudUploadAPIBase
+	}
+	return codacyclient.CodacyApiBase
+}
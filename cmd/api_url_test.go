@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	codacyclient "codacy/cli-v2/codacy-client"
+	"codacy/cli-v2/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAPIURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		flag, env, file string
+		expected        string
+		persist         bool
+	}{
+		{"default", "", "", "", codacyclient.DefaultCodacyApiBase, false},
+		{"cli-config.yaml", "", "", "https://codacy.example.com/", "https://codacy.example.com", true},
+		{"environment over cli-config.yaml", "", "http://localhost:8080", "https://codacy.example.com", "http://localhost:8080", false},
+		{"flag over everything", "https://flag.example.com", "http://localhost:8080", "https://codacy.example.com", "https://flag.example.com", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			apiURL, persist, err := resolveAPIURL(test.flag, test.env, test.file)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, apiURL)
+			assert.Equal(t, test.persist, persist)
+		})
+	}
+
+	_, _, err := resolveAPIURL("codacy.example.com", "", "")
+	assert.ErrorContains(t, err, "invalid --api-url")
+	_, _, err = resolveAPIURL("", "ftp://codacy.example.com", "")
+	assert.ErrorContains(t, err, "invalid CODACY_API_BASE_URL")
+}
+
+func TestUploadAPIBase(t *testing.T) {
+	original := codacyclient.CodacyApiBase
+	defer func() { codacyclient.CodacyApiBase = original }()
+
+	codacyclient.CodacyApiBase = codacyclient.DefaultCodacyApiBase
+	assert.Equal(t, "https://api.codacy.com", uploadAPIBase())
+
+	codacyclient.CodacyApiBase = "https://codacy.example.com"
+	assert.Equal(t, "https://codacy.example.com", uploadAPIBase())
+}
+
+func TestApplyAPIURLFromCliConfig(t *testing.T) {
+	originalConfig, originalBase, originalFlag := config.Config, codacyclient.CodacyApiBase, apiURLFlag
+	defer func() {
+		config.Config, codacyclient.CodacyApiBase, apiURLFlag = originalConfig, originalBase, originalFlag
+	}()
+
+	codacyDir := filepath.Join(t.TempDir(), ".codacy")
+	require.NoError(t, os.MkdirAll(codacyDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(codacyDir, "cli-config.yaml"), []byte("mode: local\napi_url: https://codacy.example.com/\n"), 0644))
+	config.Config = *config.NewConfigType(filepath.Dir(codacyDir), codacyDir, t.TempDir())
+	apiURLFlag = ""
+	t.Setenv(apiURLEnvVar, "")
+
+	require.NoError(t, applyAPIURL())
+	assert.Equal(t, "https://codacy.example.com", codacyclient.CodacyApiBase)
+	assert.Equal(t, "https://codacy.example.com", config.Config.APIURL())
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheCommandsSkipValidation(t *testing.T) {
+	assert.True(t, shouldSkipValidationForCommand(cacheCmd), "cache should skip validation")
+	assert.True(t, shouldSkipValidationForCommand(cacheClearCmd), "cache clear should skip validation")
+}
+
+func TestCacheClearCommandRejectsArgs(t *testing.T) {
+	assert.Error(t, cacheClearCmd.Args(cacheClearCmd, []string{"eslint"}))
+	assert.NoError(t, cacheClearCmd.Args(cacheClearCmd, []string{}))
+}
@@ -65,8 +65,12 @@ func CreateConfigurationFiles(tools []domain.Tool, cliLocalMode bool, flags doma
 
 // buildCliConfigContent creates the CLI configuration content.
 func buildCliConfigContent(cliLocalMode bool, initFlags domain.InitFlags) string {
+	content := fmt.Sprintf("mode: remote\nprovider: %s\norganization: %s\nrepository: %s", initFlags.Provider, initFlags.Organization, initFlags.Repository)
 	if cliLocalMode {
-		return fmt.Sprintf("mode: local")
+		content = "mode: local"
 	}
-	return fmt.Sprintf("mode: remote\nprovider: %s\norganization: %s\nrepository: %s", initFlags.Provider, initFlags.Organization, initFlags.Repository)
+	if apiURL := config.Config.APIURL(); apiURL != "" {
+		content += fmt.Sprintf("\napi_url: %s", apiURL)
+	}
+	return content
 }
@@ -4,6 +4,7 @@ import (
 	"codacy/cli-v2/cmd/cmdutils"
 	"codacy/cli-v2/cmd/configsetup"
 	"codacy/cli-v2/config"
+	config_file "codacy/cli-v2/config-file"
 	"codacy/cli-v2/domain"
 	"fmt"
 	"log"
@@ -60,6 +61,10 @@ var initCmd = &cobra.Command{
 			}
 		}
 		configsetup.CreateGitIgnoreFile()
+		// Cache the Codacy patterns of the configured tools, used to map their issues when uploading results
+		if err := config_file.ReadConfigFile(config.Config.ProjectConfigFile()); err == nil {
+			addConfiguredToolsToPatternCatalog()
+		}
 		fmt.Println()
 		fmt.Println("✅ Successfully initialized Codacy configuration!")
 		fmt.Println()
@@ -51,6 +51,9 @@ var installCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		// Cache the Codacy patterns of the configured tools, used to map their issues when uploading results
+		addConfiguredToolsToPatternCatalog()
+
 		// Check if anything needs to be installed
 		needsInstallation := false
 		for name, runtime := range config.Config.Runtimes() {
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	codacyclient "codacy/cli-v2/codacy-client"
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/plugins"
+
+	"github.com/spf13/cobra"
+)
+
+// toolPatternsLookup returns the Codacy tool with the given name and its patterns. Tools unknown to Codacy
+// have no patterns and no error.
+type toolPatternsLookup func(toolName string) (domain.Tool, []domain.PatternConfiguration, error)
+
+// patternLookup returns the Codacy pattern with the given id and the tool it belongs to. Patterns of no Codacy
+// tool are nil, with no error.
+type patternLookup func(patternID string) (domain.Tool, *domain.PatternDefinition, error)
+
+// patternCatalog is the part of the Codacy tools and patterns needed to map the rules reported by the tools to
+// Codacy patterns and to describe them. It is kept on disk, so uploads and analyses don't fetch every pattern on
+// every run and map rules the same way.
+type patternCatalog struct {
+	APIURL    string        `json:"apiUrl"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Tools     []catalogTool `json:"tools"`
+}
+
+type catalogTool struct {
+	UUID   string `json:"uuid"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	// PatternsFetched tells if Patterns were fetched, as most tools of the catalog are never used
+	PatternsFetched bool             `json:"patternsFetched,omitempty"`
+	Patterns        []catalogPattern `json:"patterns,omitempty"`
+}
+
+type catalogPattern struct {
+	ID          string `json:"id"`
+	Level       string `json:"level"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	TimeToFix   int    `json:"timeToFix,omitempty"`
+}
+
+// patternCatalogStore reads the pattern catalog of a Codacy API from disk, and fetches the tools and patterns
+// missing from it
+type patternCatalogStore struct {
+	path          string
+	apiURL        string
+	fetchTools    func() ([]domain.Tool, error)
+	fetchPatterns func(toolUUID string) ([]domain.PatternConfiguration, error)
+	catalog       *patternCatalog
+}
+
+// defaultPatternCatalog is the pattern catalog of the configured Codacy API, in the global Codacy directory
+func defaultPatternCatalog() *patternCatalogStore {
+	apiURL := codacyclient.CodacyApiBase
+	fileName := strings.NewReplacer("://", "_", "/", "_", ":", "_").Replace(apiURL) + ".json"
+	return &patternCatalogStore{
+		path:       filepath.Join(config.Config.CodacyDirectory(), "pattern-catalog", fileName),
+		apiURL:     apiURL,
+		fetchTools: codacyclient.GetToolsVersions,
+		fetchPatterns: func(toolUUID string) ([]domain.PatternConfiguration, error) {
+			return codacyclient.GetToolPatternsConfig(domain.InitFlags{}, toolUUID, false)
+		},
+	}
+}
+
+// load reads the catalog once. Missing or unreadable catalogs are empty, so they are fetched again.
+func (s *patternCatalogStore) load() *patternCatalog {
+	if s.catalog != nil {
+		return s.catalog
+	}
+	s.catalog = &patternCatalog{APIURL: s.apiURL}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: pattern catalog %s not read: %v", s.path, err)
+		}
+		return s.catalog
+	}
+	var catalog patternCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		log.Printf("Warning: pattern catalog %s not read: %v", s.path, err)
+		return s.catalog
+	}
+	s.catalog = &catalog
+	return s.catalog
+}
+
+func (s *patternCatalogStore) save() error {
+	s.catalog.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s.catalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pattern catalog: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), constants.DefaultDirPerms); err != nil {
+		return fmt.Errorf("failed to create pattern catalog directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, constants.DefaultFilePerms); err != nil {
+		return fmt.Errorf("failed to write pattern catalog: %w", err)
+	}
+	return nil
+}
+
+// toolPatterns looks up a Codacy tool and its patterns in the catalog, fetching and saving them when missing
+func (s *patternCatalogStore) toolPatterns(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+	changed, err := s.fetchMissing([]string{toolName})
+	if changed {
+		if err := s.save(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if err != nil {
+		return domain.Tool{}, nil, err
+	}
+
+	tool := s.load().find(toolName)
+	if tool == nil {
+		return domain.Tool{}, nil, nil
+	}
+	patterns := make([]domain.PatternConfiguration, 0, len(tool.Patterns))
+	for _, pattern := range tool.Patterns {
+		patterns = append(patterns, domain.PatternConfiguration{PatternDefinition: domain.PatternDefinition{
+			Id:            pattern.ID,
+			Level:         pattern.Level,
+			Category:      pattern.Category,
+			SeverityLevel: pattern.Severity,
+			Title:         pattern.Title,
+			Description:   pattern.Description,
+			TimeToFix:     pattern.TimeToFix,
+		}})
+	}
+	return domain.Tool{Uuid: tool.UUID, Name: tool.Name, Prefix: tool.Prefix}, patterns, nil
+}
+
+// pattern looks up a Codacy pattern by its exact id, with the tool it belongs to: the tool whose prefix starts the
+// id. The patterns of that tool are fetched and saved when missing. Patterns of no Codacy tool are nil.
+func (s *patternCatalogStore) pattern(patternID string) (domain.Tool, *domain.PatternDefinition, error) {
+	changed, err := s.fetchMissing(nil)
+	if changed {
+		if err := s.save(); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if err != nil {
+		return domain.Tool{}, nil, err
+	}
+
+	catalog := s.load()
+	var owner *catalogTool
+	for i := range catalog.Tools {
+		tool := &catalog.Tools[i]
+		if tool.Prefix != "" && strings.HasPrefix(patternID, tool.Prefix) && (owner == nil || len(tool.Prefix) > len(owner.Prefix)) {
+			owner = tool
+		}
+	}
+	if owner == nil {
+		return domain.Tool{}, nil, nil
+	}
+
+	tool, patterns, err := s.toolPatterns(owner.Name)
+	if err != nil {
+		return domain.Tool{}, nil, err
+	}
+	for _, pattern := range patterns {
+		if pattern.PatternDefinition.Id == patternID {
+			return tool, &pattern.PatternDefinition, nil
+		}
+	}
+	return tool, nil, nil
+}
+
+// fetchMissing fetches the tools list when the catalog has none, and the patterns of the given tools that were
+// not fetched yet. It tells if the catalog changed.
+func (s *patternCatalogStore) fetchMissing(toolNames []string) (bool, error) {
+	catalog := s.load()
+	changed := false
+	if len(catalog.Tools) == 0 {
+		if err := s.fetchToolsList(); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	for _, toolName := range toolNames {
+		tool := catalog.find(toolName)
+		if tool == nil || tool.PatternsFetched {
+			continue
+		}
+		if err := s.fetchToolPatterns(tool); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// fetchToolsList replaces the tools of the catalog, keeping the patterns of the tools that still exist
+func (s *patternCatalogStore) fetchToolsList() error {
+	codacyTools, err := s.fetchTools()
+	if err != nil {
+		return fmt.Errorf("failed to fetch the Codacy tools: %w", err)
+	}
+
+	catalog := s.load()
+	tools := make([]catalogTool, 0, len(codacyTools))
+	for _, codacyTool := range codacyTools {
+		tool := catalogTool{UUID: codacyTool.Uuid, Name: codacyTool.Name, Prefix: codacyTool.Prefix}
+		if previous := catalog.find(codacyTool.Name); previous != nil && previous.UUID == tool.UUID {
+			tool.PatternsFetched, tool.Patterns = previous.PatternsFetched, previous.Patterns
+		}
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	catalog.Tools = tools
+	catalog.APIURL = s.apiURL
+	return nil
+}
+
+func (s *patternCatalogStore) fetchToolPatterns(tool *catalogTool) error {
+	configurations, err := s.fetchPatterns(tool.UUID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the patterns of %s: %w", tool.Name, err)
+	}
+	tool.Patterns = make([]catalogPattern, 0, len(configurations))
+	for _, configuration := range configurations {
+		definition := configuration.PatternDefinition
+		tool.Patterns = append(tool.Patterns, catalogPattern{
+			ID:          definition.Id,
+			Level:       definition.Level,
+			Category:    definition.Category,
+			Severity:    definition.SeverityLevel,
+			Title:       definition.Title,
+			Description: definition.Description,
+			TimeToFix:   definition.TimeToFix,
+		})
+	}
+	tool.PatternsFetched = true
+	return nil
+}
+
+// refresh fetches the tools list again, and the patterns of the given tools and of the tools whose patterns
+// were already in the catalog
+func (s *patternCatalogStore) refresh(toolNames []string) error {
+	catalog := s.load()
+	for _, tool := range catalog.Tools {
+		if tool.PatternsFetched {
+			toolNames = append(toolNames, tool.Name)
+		}
+	}
+	if err := s.fetchToolsList(); err != nil {
+		return err
+	}
+	for i := range catalog.Tools {
+		catalog.Tools[i].PatternsFetched, catalog.Tools[i].Patterns = false, nil
+	}
+	if _, err := s.fetchMissing(toolNames); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// find returns the tool with the given name, ignoring case
+func (c *patternCatalog) find(toolName string) *catalogTool {
+	for i := range c.Tools {
+		if strings.EqualFold(c.Tools[i].Name, toolName) {
+			return &c.Tools[i]
+		}
+	}
+	return nil
+}
+
+// configuredCodacyToolNames maps the configured tools to the names of their Codacy tools
+func configuredCodacyToolNames(tools map[string]*plugins.ToolInfo) []string {
+	names := make([]string, 0, len(tools))
+	for name, tool := range tools {
+		names = append(names, getToolName(strings.ToLower(name), tool.Version))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addConfiguredToolsToPatternCatalog fetches the patterns of the configured tools missing from the catalog,
+// so uploads don't need to. Failures are only reported, as the patterns are fetched again when uploading.
+func addConfiguredToolsToPatternCatalog() {
+	store := defaultPatternCatalog()
+	changed, err := store.fetchMissing(configuredCodacyToolNames(config.Config.Tools()))
+	if changed {
+		if saveErr := store.save(); saveErr != nil {
+			err = saveErr
+		}
+	}
+	if err != nil {
+		log.Printf("Warning: Codacy patterns not cached, they will be fetched when uploading: %v", err)
+	}
+}
+
+var patternsCmd = &cobra.Command{
+	Use:   "patterns",
+	Short: "Manage the catalog of Codacy patterns",
+	Long:  "Manage the catalog of Codacy patterns used to map the rules reported by the tools to Codacy patterns when uploading results.",
+}
+
+var patternsRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch the Codacy tools and patterns again",
+	Long:  "Fetch the Codacy tools again, with the patterns of the configured tools and of the tools already in the catalog.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store := defaultPatternCatalog()
+		if err := store.refresh(configuredCodacyToolNames(config.Config.Tools())); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Refreshed the pattern catalog at %s\n", store.path)
+	},
+}
+
+func init() {
+	patternsCmd.AddCommand(patternsRefreshCmd)
+	rootCmd.AddCommand(patternsCmd)
+}
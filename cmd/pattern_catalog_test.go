@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/plugins"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPatternCatalog creates a catalog store in a temporary file counting the fetches of tools and patterns
+func stubPatternCatalog(path string, toolFetches *int, patternFetches map[string]int) *patternCatalogStore {
+	return &patternCatalogStore{
+		path:   path,
+		apiURL: "https://app.codacy.com",
+		fetchTools: func() ([]domain.Tool, error) {
+			*toolFetches++
+			return []domain.Tool{
+				{Uuid: "trivy-uuid", Name: "Trivy", Prefix: "Trivy_"},
+				{Uuid: "eslint-uuid", Name: "ESLint9", Prefix: "ESLint9_"},
+			}, nil
+		},
+		fetchPatterns: func(toolUUID string) ([]domain.PatternConfiguration, error) {
+			patternFetches[toolUUID]++
+			return []domain.PatternConfiguration{
+				{PatternDefinition: domain.PatternDefinition{
+					Id: toolUUID + "_rule", Level: "Warning", Category: "Security", SeverityLevel: "High",
+					Title: "Rule", Description: "Checks the rule", TimeToFix: 5, Enabled: true,
+				}},
+			}, nil
+		},
+	}
+}
+
+func TestPatternCatalogFetchesOnceAndPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(path, &toolFetches, patternFetches)
+
+	for i := 0; i < 2; i++ {
+		tool, patterns, err := store.toolPatterns("trivy")
+		require.NoError(t, err)
+		assert.Equal(t, domain.Tool{Uuid: "trivy-uuid", Name: "Trivy", Prefix: "Trivy_"}, tool)
+		assert.Equal(t, []domain.PatternConfiguration{
+			{PatternDefinition: domain.PatternDefinition{
+				Id: "trivy-uuid_rule", Level: "Warning", Category: "Security", SeverityLevel: "High",
+				Title: "Rule", Description: "Checks the rule", TimeToFix: 5,
+			}},
+		}, patterns)
+	}
+	assert.Equal(t, 1, toolFetches)
+	assert.Equal(t, map[string]int{"trivy-uuid": 1}, patternFetches)
+
+	// A new store reads the catalog from disk, and only fetches the patterns of tools not fetched yet
+	reloaded := stubPatternCatalog(path, &toolFetches, patternFetches)
+	_, patterns, err := reloaded.toolPatterns("Trivy")
+	require.NoError(t, err)
+	require.Len(t, patterns, 1)
+	assert.Equal(t, "Checks the rule", patterns[0].PatternDefinition.Description)
+	_, patterns, err = reloaded.toolPatterns("ESLint9")
+	require.NoError(t, err)
+	assert.Len(t, patterns, 1)
+	assert.Equal(t, 1, toolFetches)
+	assert.Equal(t, map[string]int{"trivy-uuid": 1, "eslint-uuid": 1}, patternFetches)
+}
+
+func TestPatternCatalogUnknownTool(t *testing.T) {
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(filepath.Join(t.TempDir(), "catalog.json"), &toolFetches, patternFetches)
+
+	tool, patterns, err := store.toolPatterns("unknown")
+
+	require.NoError(t, err)
+	assert.Empty(t, tool.Name)
+	assert.Empty(t, patterns)
+	assert.Empty(t, patternFetches)
+}
+
+func TestPatternCatalogFetchErrors(t *testing.T) {
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(filepath.Join(t.TempDir(), "catalog.json"), &toolFetches, patternFetches)
+	store.fetchPatterns = func(toolUUID string) ([]domain.PatternConfiguration, error) {
+		return nil, errors.New("offline")
+	}
+
+	_, _, err := store.toolPatterns("Trivy")
+	assert.ErrorContains(t, err, "failed to fetch the patterns of Trivy: offline")
+
+	store = stubPatternCatalog(filepath.Join(t.TempDir(), "catalog.json"), &toolFetches, patternFetches)
+	store.fetchTools = func() ([]domain.Tool, error) {
+		return nil, errors.New("offline")
+	}
+	_, _, err = store.toolPatterns("Trivy")
+	assert.ErrorContains(t, err, "failed to fetch the Codacy tools: offline")
+}
+
+func TestPatternCatalogRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(path, &toolFetches, patternFetches)
+	_, _, err := store.toolPatterns("Trivy")
+	require.NoError(t, err)
+
+	require.NoError(t, store.refresh([]string{"ESLint9"}))
+
+	assert.Equal(t, 2, toolFetches)
+	assert.Equal(t, map[string]int{"trivy-uuid": 2, "eslint-uuid": 1}, patternFetches)
+
+	reloaded := stubPatternCatalog(path, &toolFetches, patternFetches)
+	_, patterns, err := reloaded.toolPatterns("ESLint9")
+	require.NoError(t, err)
+	assert.Len(t, patterns, 1)
+	assert.Equal(t, 2, toolFetches)
+	assert.Equal(t, 1, patternFetches["eslint-uuid"])
+}
+
+func TestConfiguredCodacyToolNames(t *testing.T) {
+	names := configuredCodacyToolNames(map[string]*plugins.ToolInfo{
+		"eslint": {Name: "eslint", Version: "9.1.0"},
+		"pylint": {Name: "pylint", Version: "3.3.6"},
+		"trivy":  {Name: "trivy", Version: "0.59.1"},
+	})
+
+	assert.Equal(t, []string{"ESLint9", "Trivy", "pylintpython3"}, names)
+}
+
+func TestPatternCatalogPattern(t *testing.T) {
+	toolFetches, patternFetches := 0, map[string]int{}
+	store := stubPatternCatalog(filepath.Join(t.TempDir(), "catalog.json"), &toolFetches, patternFetches)
+	store.fetchPatterns = func(toolUUID string) ([]domain.PatternConfiguration, error) {
+		patternFetches[toolUUID]++
+		return []domain.PatternConfiguration{
+			{PatternDefinition: domain.PatternDefinition{Id: "ESLint9_no-eval-strict", Level: "Error"}},
+			{PatternDefinition: domain.PatternDefinition{Id: "ESLint9_no-eval", Level: "Warning"}},
+		}, nil
+	}
+
+	tool, pattern, err := store.pattern("ESLint9_no-eval")
+	require.NoError(t, err)
+	assert.Equal(t, "ESLint9", tool.Name)
+	require.NotNil(t, pattern)
+	assert.Equal(t, "Warning", pattern.Level)
+
+	// Patterns are looked up by their exact id, in the tool whose prefix starts the id
+	_, pattern, err = store.pattern("ESLint9_no-ev")
+	require.NoError(t, err)
+	assert.Nil(t, pattern)
+	_, pattern, err = store.pattern("Unknown_no-eval")
+	require.NoError(t, err)
+	assert.Nil(t, pattern)
+
+	assert.Equal(t, map[string]int{"eslint-uuid": 1}, patternFetches)
+}
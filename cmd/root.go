@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	codacyclient "codacy/cli-v2/codacy-client"
 	"codacy/cli-v2/config"
 	"codacy/cli-v2/utils/logger"
 	"codacy/cli-v2/version"
@@ -37,8 +38,13 @@ var rootCmd = &cobra.Command{
 			"args":         args,
 		})
 
+		if err := applyAPIURL(); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Validate codacy.yaml for all commands except init, help, and version
-		if !shouldSkipValidation(cmd.Name()) {
+		if !shouldSkipValidationForCommand(cmd) {
 			if err := validateCodacyYAML(); err != nil {
 				logger.Error("Global validation failed", logrus.Fields{
 					"command": cmd.Name(),
@@ -107,6 +113,7 @@ func getExampleText() string {
 func init() {
 	// Add global flags here
 	rootCmd.PersistentFlags().String("config", filepath.Join(".codacy", "codacy.yaml"), "config file")
+	rootCmd.PersistentFlags().StringVar(&apiURLFlag, "api-url", "", "Base URL of the Codacy API, e.g. of a self-hosted installation (default "+codacyclient.DefaultCodacyApiBase+", or $"+apiURLEnvVar+")")
 
 	// Customize help template
 	rootCmd.SetUsageTemplate(`
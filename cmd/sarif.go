@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/utils"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	sarifTextFormat = "text"
+	sarifJSONFormat = "json"
+)
+
+var sarifOutputFile string
+var sarifReportFormat string
+var sarifFilterCriteria sarifFilter
+
+var sarifCmd = &cobra.Command{
+	Use:   "sarif",
+	Short: "Process SARIF files",
+	Long:  "Merge, filter, compare and summarize SARIF files, from this CLI or any other scanner. These commands work offline and don't need a codacy.yaml.",
+}
+
+var sarifMergeCmd = &cobra.Command{
+	Use:   "merge <file>...",
+	Short: "Merge the runs of SARIF files into a single SARIF file",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitOnSarifError(mergeSarifFiles(args, sarifOutputFile))
+	},
+}
+
+var sarifFilterCmd = &cobra.Command{
+	Use:   "filter <file>",
+	Short: "Keep the results of a SARIF file matching the given tools, rules, levels and paths",
+	Long: "Keep the results of a SARIF file matching all the given criteria. Each criterion can be repeated " +
+		"to match any of its values. Paths are glob patterns relative to the current directory, where ** matches " +
+		"any number of directories and patterns without a slash match the file name.",
+	Example: "  codacy-cli sarif filter results.sarif --tool eslint --level error --path 'src/**/*.ts'",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitOnSarifError(filterSarifFile(args[0], sarifFilterCriteria, sarifOutputFile))
+	},
+}
+
+var sarifDiffCmd = &cobra.Command{
+	Use:   "diff <base.sarif> <head.sarif>",
+	Short: "Report the new, fixed and unchanged results between two SARIF files",
+	Long: "Report the new, fixed and unchanged results between two SARIF files. Results are matched by fingerprint, " +
+		"so results whose lines moved are unchanged. Files are read from the current directory to compute the fingerprints.",
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitOnSarifError(diffSarifFiles(args[0], args[1], sarifReportFormat, os.Stdout))
+	},
+}
+
+var sarifStatsCmd = &cobra.Command{
+	Use:   "stats <file>...",
+	Short: "Count the results of SARIF files by tool and level",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		exitOnSarifError(sarifStats(args, sarifReportFormat, os.Stdout))
+	},
+}
+
+func init() {
+	for _, command := range []*cobra.Command{sarifMergeCmd, sarifFilterCmd} {
+		command.Flags().StringVarP(&sarifOutputFile, "output", "o", "", "Output file, instead of the console")
+	}
+	for _, command := range []*cobra.Command{sarifDiffCmd, sarifStatsCmd} {
+		command.Flags().StringVar(&sarifReportFormat, "format", sarifTextFormat, "Output format: text or json")
+	}
+	sarifFilterCmd.Flags().StringArrayVar(&sarifFilterCriteria.tools, "tool", nil, "Keep the runs of this tool, e.g. eslint")
+	sarifFilterCmd.Flags().StringArrayVar(&sarifFilterCriteria.rules, "rule", nil, "Keep the results of this rule id")
+	sarifFilterCmd.Flags().StringArrayVar(&sarifFilterCriteria.levels, "level", nil, "Keep the results of this level: error, warning, note or none")
+	sarifFilterCmd.Flags().StringArrayVar(&sarifFilterCriteria.paths, "path", nil, "Keep the results in files matching this glob pattern")
+
+	sarifCmd.AddCommand(sarifMergeCmd, sarifFilterCmd, sarifDiffCmd, sarifStatsCmd)
+	rootCmd.AddCommand(sarifCmd)
+}
+
+func exitOnSarifError(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// mergeSarifFiles merges the runs of the input files into outputFile, or to the console when no file is given
+func mergeSarifFiles(inputFiles []string, outputFile string) error {
+	// MergeSarifOutputs skips missing files, as tools may fail to write them, but files given explicitly must exist
+	for _, file := range inputFiles {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("failed to read SARIF file: %w", err)
+		}
+	}
+	if outputFile != "" {
+		return utils.MergeSarifOutputs(inputFiles, outputFile)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "codacy-sarif-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	mergedFile := filepath.Join(tmpDir, "merged.sarif")
+	if err := utils.MergeSarifOutputs(inputFiles, mergedFile); err != nil {
+		return err
+	}
+	mergedData, err := os.ReadFile(mergedFile)
+	if err != nil {
+		return fmt.Errorf("failed to read merged SARIF: %w", err)
+	}
+	return writeSarifOutput([]byte(strings.TrimSuffix(string(mergedData), "\n")), "")
+}
+
+// sarifFilter selects SARIF results. Results must match every criterion, and any value of a criterion.
+type sarifFilter struct {
+	tools  []string
+	rules  []string
+	levels []string
+	paths  []string
+}
+
+// keepsTool checks if the runs of a tool, given by its SARIF driver name, are kept
+func (f sarifFilter) keepsTool(driverName string) bool {
+	if len(f.tools) == 0 {
+		return true
+	}
+	for _, tool := range f.tools {
+		if utils.MatchesToolName(driverName, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// keeps checks if a result is kept
+func (f sarifFilter) keeps(issue domain.Issue) bool {
+	return matchesAny(f.rules, func(rule string) bool { return strings.EqualFold(rule, issue.PatternID) }) &&
+		matchesAny(f.levels, func(level string) bool { return strings.EqualFold(level, issue.Level) }) &&
+		matchesAny(f.paths, func(pattern string) bool { return issue.Path != "" && utils.MatchPathGlob(pattern, issue.Path) })
+}
+
+// matchesAny checks if any of the values matches, or if there are no values to match
+func matchesAny(values []string, matches func(value string) bool) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if matches(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSarifFile writes the runs and results of a SARIF file kept by the filter to outputFile, or to the console
+func filterSarifFile(inputFile string, filter sarifFilter, outputFile string) error {
+	sarifData, err := os.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read SARIF file: %w", err)
+	}
+	workDirectory, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	sarifData, _, err = utils.FilterSarifRuns(sarifData, filter.keepsTool)
+	if err != nil {
+		return err
+	}
+	sarifData, _, err = utils.FilterSarifResults(sarifData, workDirectory, filter.keeps)
+	if err != nil {
+		return err
+	}
+	return writeSarifOutput(sarifData, outputFile)
+}
+
+// readSarifIssues parses the issues of SARIF files, with paths relative to the current directory
+func readSarifIssues(files ...string) ([][]domain.ToolResults, error) {
+	workDirectory, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	results := make([][]domain.ToolResults, 0, len(files))
+	for _, file := range files {
+		sarifData, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SARIF file: %w", err)
+		}
+		toolResults, err := utils.ParseSarifIssues(sarifData, workDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		results = append(results, toolResults)
+	}
+	return results, nil
+}
+
+// diffSarifFiles writes the new, fixed and unchanged results of the head file compared to the base file
+func diffSarifFiles(baseFile string, headFile string, format string, w io.Writer) error {
+	if err := validateSarifReportFormat(format); err != nil {
+		return err
+	}
+	results, err := readSarifIssues(baseFile, headFile)
+	if err != nil {
+		return err
+	}
+	diff := utils.DiffIssues(results[0], results[1])
+
+	if format == sarifJSONFormat {
+		return writeJSON(w, diff)
+	}
+
+	for _, section := range []struct {
+		title  string
+		issues []domain.Issue
+	}{{"New", diff.New}, {"Fixed", diff.Fixed}} {
+		if len(section.issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.issues))
+		for _, issue := range section.issues {
+			fmt.Fprintf(w, "  %s  %s  %s  %s  %s\n", sarifIssueLocation(issue), issue.Level, issue.Tool, issue.PatternID, strings.Join(strings.Fields(issue.Message), " "))
+		}
+		fmt.Fprintln(w)
+	}
+	_, err = fmt.Fprintf(w, "%d new, %d fixed, %d unchanged\n", len(diff.New), len(diff.Fixed), len(diff.Unchanged))
+	return err
+}
+
+// sarifIssueLocation formats the location of an issue as path:line:column
+func sarifIssueLocation(issue domain.Issue) string {
+	location := issue.Path
+	if location == "" {
+		location = "(no file)"
+	}
+	if issue.Region.StartLine > 0 {
+		location += fmt.Sprintf(":%d", issue.Region.StartLine)
+		if issue.Region.StartColumn > 0 {
+			location += fmt.Sprintf(":%d", issue.Region.StartColumn)
+		}
+	}
+	return location
+}
+
+// sarifToolStats is the number of results of a tool by level
+type sarifToolStats struct {
+	Tool       string         `json:"tool"`
+	Levels     map[string]int `json:"levels"`
+	Total      int            `json:"total"`
+	Suppressed int            `json:"suppressed"`
+}
+
+// sarifStatsLevels are the levels shown by the stats command
+var sarifStatsLevels = []string{"error", "warning", "note", "none"}
+
+// sarifStats writes the number of results of each tool by level, adding up the results of every file
+func sarifStats(files []string, format string, w io.Writer) error {
+	if err := validateSarifReportFormat(format); err != nil {
+		return err
+	}
+	results, err := readSarifIssues(files...)
+	if err != nil {
+		return err
+	}
+
+	var stats []*sarifToolStats
+	statsByTool := make(map[string]*sarifToolStats)
+	for _, toolResults := range results {
+		for _, run := range toolResults {
+			toolStats, ok := statsByTool[run.Tool]
+			if !ok {
+				toolStats = &sarifToolStats{Tool: run.Tool, Levels: make(map[string]int)}
+				statsByTool[run.Tool] = toolStats
+				stats = append(stats, toolStats)
+			}
+			for _, issue := range run.Issues {
+				if issue.Suppressed {
+					toolStats.Suppressed++
+					continue
+				}
+				toolStats.Levels[issue.Level]++
+				toolStats.Total++
+			}
+		}
+	}
+
+	if format == sarifJSONFormat {
+		return writeJSON(w, stats)
+	}
+
+	totals := sarifToolStats{Tool: "Total", Levels: make(map[string]int)}
+	for _, toolStats := range stats {
+		for level, count := range toolStats.Levels {
+			totals.Levels[level] += count
+		}
+		totals.Total += toolStats.Total
+		totals.Suppressed += toolStats.Suppressed
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Tool\tError\tWarning\tNote\tNone\tTotal\tSuppressed")
+	for _, toolStats := range append(stats, &totals) {
+		row := []string{toolStats.Tool}
+		for _, level := range sarifStatsLevels {
+			row = append(row, fmt.Sprint(toolStats.Levels[level]))
+		}
+		row = append(row, fmt.Sprint(toolStats.Total), fmt.Sprint(toolStats.Suppressed))
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+	return table.Flush()
+}
+
+func validateSarifReportFormat(format string) error {
+	if format != sarifTextFormat && format != sarifJSONFormat {
+		return fmt.Errorf("unsupported format %s, use %s or %s", format, sarifTextFormat, sarifJSONFormat)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/utils"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eslintSarif = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "ESLint", "rules": [{"id": "semi"}]}},
+    "results": [
+      {"ruleId": "semi", "level": "error", "message": {"text": "Missing semicolon."},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/app.js"}, "region": {"startLine": 1}}}]},
+      {"ruleId": "no-unused-vars", "level": "warning", "message": {"text": "'a' is unused."},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "test/app.test.js"}, "region": {"startLine": 2}}}]}
+    ]
+  }]
+}`
+
+const pylintSarif = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "Pylint"}},
+    "results": [
+      {"ruleId": "C0114", "level": "note", "message": {"text": "Missing module docstring"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/main.py"}, "region": {"startLine": 1}}}]}
+    ]
+  }]
+}`
+
+// writeSarifFiles writes the SARIF files and source files the results point to in a temporary working directory
+func writeSarifFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	sources := map[string]string{
+		"src/app.js":       "const a = 1\n",
+		"test/app.test.js": "let b = 2;\nconst a = 1;\n",
+		"src/main.py":      "print('hi')\n",
+	}
+	for name, content := range sources {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func readIssues(t *testing.T, file string) map[string][]string {
+	results, err := readSarifIssues(file)
+	require.NoError(t, err)
+	rules := make(map[string][]string)
+	for _, run := range results[0] {
+		rules[run.Tool] = []string{}
+		for _, issue := range run.Issues {
+			rules[run.Tool] = append(rules[run.Tool], issue.PatternID)
+		}
+	}
+	return rules
+}
+
+func TestSarifCommandsSkipValidation(t *testing.T) {
+	for _, command := range []*cobra.Command{sarifCmd, sarifMergeCmd, sarifFilterCmd, sarifDiffCmd, sarifStatsCmd} {
+		assert.True(t, shouldSkipValidationForCommand(command), "%s should skip validation", command.CommandPath())
+	}
+
+	// Other commands with the same names are validated
+	root := &cobra.Command{Use: "codacy-cli"}
+	group := &cobra.Command{Use: "report"}
+	merge := &cobra.Command{Use: "merge"}
+	group.AddCommand(merge)
+	root.AddCommand(group)
+	assert.False(t, shouldSkipValidationForCommand(merge))
+}
+
+func TestMergeSarifFiles(t *testing.T) {
+	writeSarifFiles(t, map[string]string{"eslint.sarif": eslintSarif, "pylint.sarif": pylintSarif})
+
+	require.NoError(t, mergeSarifFiles([]string{"eslint.sarif", "pylint.sarif"}, "merged.sarif"))
+
+	assert.Equal(t, map[string][]string{
+		"ESLint": {"semi", "no-unused-vars"},
+		"Pylint": {"C0114"},
+	}, readIssues(t, "merged.sarif"))
+
+	err := mergeSarifFiles([]string{"eslint.sarif", "missing.sarif"}, "merged.sarif")
+	assert.ErrorContains(t, err, "missing.sarif")
+}
+
+func TestFilterSarifFile(t *testing.T) {
+	writeSarifFiles(t, map[string]string{"eslint.sarif": eslintSarif, "pylint.sarif": pylintSarif})
+	require.NoError(t, mergeSarifFiles([]string{"eslint.sarif", "pylint.sarif"}, "merged.sarif"))
+
+	tests := []struct {
+		name     string
+		filter   sarifFilter
+		expected map[string][]string
+	}{
+		{"no criteria", sarifFilter{}, map[string][]string{"ESLint": {"semi", "no-unused-vars"}, "Pylint": {"C0114"}}},
+		{"tool", sarifFilter{tools: []string{"eslint"}}, map[string][]string{"ESLint": {"semi", "no-unused-vars"}}},
+		{"rule", sarifFilter{rules: []string{"c0114", "semi"}}, map[string][]string{"ESLint": {"semi"}, "Pylint": {"C0114"}}},
+		{"level", sarifFilter{levels: []string{"warning"}}, map[string][]string{"ESLint": {"no-unused-vars"}, "Pylint": {}}},
+		{"path", sarifFilter{paths: []string{"src/**"}}, map[string][]string{"ESLint": {"semi"}, "Pylint": {"C0114"}}},
+		{"all criteria", sarifFilter{tools: []string{"eslint"}, paths: []string{"*.js"}, levels: []string{"error"}}, map[string][]string{"ESLint": {"semi"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, filterSarifFile("merged.sarif", test.filter, "filtered.sarif"))
+			assert.Equal(t, test.expected, readIssues(t, "filtered.sarif"))
+		})
+	}
+}
+
+func TestDiffSarifFiles(t *testing.T) {
+	head := `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "ESLint"}},
+    "results": [
+      {"ruleId": "no-unused-vars", "level": "warning", "message": {"text": "'a' is unused."},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "test/app.test.js"}, "region": {"startLine": 2}}}]},
+      {"ruleId": "eqeqeq", "level": "error", "message": {"text": "Expected '==='."},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/app.js"}, "region": {"startLine": 1, "startColumn": 7}}}]}
+    ]
+  }]
+}`
+	writeSarifFiles(t, map[string]string{"base.sarif": eslintSarif, "head.sarif": head})
+
+	var output bytes.Buffer
+	require.NoError(t, diffSarifFiles("base.sarif", "head.sarif", sarifTextFormat, &output))
+	assert.Equal(t, "New (1):\n"+
+		"  src/app.js:1:7  error  ESLint  eqeqeq  Expected '==='.\n\n"+
+		"Fixed (1):\n"+
+		"  src/app.js:1  error  ESLint  semi  Missing semicolon.\n\n"+
+		"1 new, 1 fixed, 1 unchanged\n", output.String())
+
+	output.Reset()
+	require.NoError(t, diffSarifFiles("base.sarif", "head.sarif", sarifJSONFormat, &output))
+	var diff utils.IssuesDiff
+	require.NoError(t, json.Unmarshal(output.Bytes(), &diff))
+	assert.Len(t, diff.New, 1)
+	assert.Len(t, diff.Fixed, 1)
+	assert.Len(t, diff.Unchanged, 1)
+
+	assert.Error(t, diffSarifFiles("base.sarif", "head.sarif", "xml", &output))
+}
+
+func TestSarifStats(t *testing.T) {
+	writeSarifFiles(t, map[string]string{"eslint.sarif": eslintSarif, "pylint.sarif": pylintSarif})
+
+	var output bytes.Buffer
+	require.NoError(t, sarifStats([]string{"eslint.sarif", "pylint.sarif"}, sarifTextFormat, &output))
+	assert.Equal(t, ""+
+		"Tool    Error  Warning  Note  None  Total  Suppressed\n"+
+		"ESLint  1      1        0     0     2      0\n"+
+		"Pylint  0      0        1     0     1      0\n"+
+		"Total   1      1        1     0     3      0\n", output.String())
+
+	output.Reset()
+	require.NoError(t, sarifStats([]string{"eslint.sarif"}, sarifJSONFormat, &output))
+	var stats []sarifToolStats
+	require.NoError(t, json.Unmarshal(output.Bytes(), &stats))
+	assert.Equal(t, []sarifToolStats{{Tool: "ESLint", Levels: map[string]int{"error": 1, "warning": 1}, Total: 2}}, stats)
+}
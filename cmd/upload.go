@@ -1,19 +1,16 @@
 package cmd
 
 import (
-	"bytes"
 	"codacy/cli-v2/config"
 	"codacy/cli-v2/domain"
 	"codacy/cli-v2/plugins"
+	"codacy/cli-v2/utils"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -22,6 +19,14 @@ var apiToken string
 var provider string
 var owner string
 var repository string
+var uploadTimeout time.Duration
+var uploadRetries int
+var uploadDryRun bool
+var uploadPayloadOut string
+
+// maxUploadChunkBytes bounds the size of each results request, as the results of a tool on large repositories
+// are too big for a single request
+const maxUploadChunkBytes = 4 * 1024 * 1024
 
 func init() {
 	uploadResultsCmd.Flags().StringVarP(&sarifPath, "sarif-path", "s", "", "Path to the SARIF report")
@@ -32,6 +37,10 @@ func init() {
 	uploadResultsCmd.Flags().StringVarP(&provider, "provider", "p", "", "Provider (gh, gl, bb)")
 	uploadResultsCmd.Flags().StringVarP(&owner, "owner", "o", "", "Owner/Organization")
 	uploadResultsCmd.Flags().StringVarP(&repository, "repository", "r", "", "Repository")
+	uploadResultsCmd.Flags().DurationVar(&uploadTimeout, "timeout", defaultUploadTimeout, "Maximum time of each request to Codacy, e.g. 90s")
+	uploadResultsCmd.Flags().IntVar(&uploadRetries, "retries", defaultUploadRetries, "Number of times a request failing to connect, or with a 429 or 5xx status, is retried")
+	uploadResultsCmd.Flags().BoolVar(&uploadDryRun, "dry-run", false, "Map the SARIF results to Codacy and summarize the mapped and unmapped rules without sending anything")
+	uploadResultsCmd.Flags().StringVar(&uploadPayloadOut, "payload-out", "", "Write the request bodies sent to Codacy to this JSON file")
 
 	rootCmd.AddCommand(uploadResultsCmd)
 }
@@ -41,7 +50,11 @@ var uploadResultsCmd = &cobra.Command{
 	Short: "Uploads a sarif file to Codacy",
 	Long:  "YADA",
 	Run: func(cmd *cobra.Command, args []string) {
-		processSarifAndSendResults(sarifPath, commitUuid, projectToken, apiToken, config.Config.Tools())
+		client := newUploadClient(uploadTimeout, uploadRetries)
+		if err := processSarifAndSendResults(client, sarifPath, commitUuid, projectToken, apiToken, config.Config.Tools()); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			exitFunc(1)
+		}
 	},
 }
 
@@ -68,229 +81,369 @@ func getToolShortName(fullName string) string {
 	return fullName
 }
 
-func getRelativePath(baseDir string, fullURI string) string {
-
-	localPath := fullURI
-	u, err := url.Parse(fullURI)
-	if err == nil && u.Scheme == "file" {
-		// url.Path extracts the local path component correctly
-		localPath = u.Path
+func processSarifAndSendResults(client *uploadClient, sarifPath string, commitUUID string, projectToken string, apiToken string, tools map[string]*plugins.ToolInfo) error {
+	if !uploadDryRun && projectToken == "" && apiToken == "" && provider == "" && repository == "" {
+		return fmt.Errorf("api-token, provider and repository are required when project-token is not provided")
 	}
-	relativePath, err := filepath.Rel(baseDir, localPath)
+	//Load SARIF file
+	fmt.Printf("Loading SARIF file from path: %s\n", sarifPath)
+	sarifData, err := os.ReadFile(sarifPath)
 	if err != nil {
-		// Fallback to the normalized absolute path if calculation fails
-		fmt.Printf("Warning: Could not get relative path for '%s' relative to '%s': %v. Using absolute path.\n", localPath, baseDir, err)
-		return localPath
+		return fmt.Errorf("error opening SARIF file: %w", err)
 	}
 
-	return relativePath
-}
-
-func processSarifAndSendResults(sarifPath string, commitUUID string, projectToken string, apiToken string, tools map[string]*plugins.ToolInfo) {
-	if projectToken == "" && apiToken == "" && provider == "" && repository == "" {
-		fmt.Println("Error: api-token, provider and repository are required when project-token is not provided")
-		os.Exit(1)
-	}
-	//Load SARIF file
-	fmt.Printf("Loading SARIF file from path: %s\n", sarifPath)
-	sarifFile, err := os.Open(sarifPath)
+	baseDir, err := os.Getwd()
 	if err != nil {
-		fmt.Printf("Error opening SARIF file: %v\n", err)
-		panic("panic")
+		return fmt.Errorf("error getting current working directory: %w", err)
 	}
-	defer sarifFile.Close()
 
-	var sarif Sarif
 	fmt.Println("Parsing SARIF file...")
-	err = json.NewDecoder(sarifFile).Decode(&sarif)
+	toolResults, err := utils.ParseSarifIssues(sarifData, baseDir)
+	if err != nil {
+		return fmt.Errorf("error parsing SARIF file: %w", err)
+	}
+
+	ruleMappings, err := loadRuleMappings(ruleMappingsPath())
 	if err != nil {
-		fmt.Printf("Error parsing SARIF file: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
 	fmt.Println("Loading Codacy patterns...")
-	payloads := processSarif(sarif, tools)
-	if projectToken != "" {
-		for _, payload := range payloads {
-			sendResultsWithProjectToken(payload, commitUUID, projectToken)
+	catalog := defaultPatternCatalog()
+	payloads, mappings, err := processSarif(toolResults, tools, catalog.toolPatterns, catalog.pattern, ruleMappings)
+	if err != nil {
+		return err
+	}
+	metricsPayloads := processMetrics(toolResults)
+	duplicationPayloads := processDuplication(toolResults)
+	if uploadPayloadOut != "" {
+		allPayloads := append(append(payloads, metricsPayloads...), duplicationPayloads...)
+		if err := writeUploadPayloads(allPayloads, uploadPayloadOut); err != nil {
+			return err
 		}
+		fmt.Printf("Request bodies written to %s\n", uploadPayloadOut)
+	}
+	if uploadDryRun {
+		printRuleMappings(os.Stdout, mappings, len(payloads)+len(metricsPayloads)+len(duplicationPayloads))
+		printMetricsSummary(os.Stdout, metricsPayloads)
+		printDuplicationSummary(os.Stdout, duplicationPayloads)
+		return nil
+	}
 
-		resultsFinalWithProjectToken(commitUUID, projectToken)
-	} else {
+	if err := sendPayloads(client, payloads, metricsPayloads, duplicationPayloads, commitUUID, projectToken, apiToken); err != nil {
+		return err
+	}
+	return printUnmappedRules(os.Stdout, mappings)
+}
+
+// sendPayloads sends the results, the file metrics and the duplication to Codacy, and tells it all results were sent
+func sendPayloads(client *uploadClient, payloads [][]map[string]interface{}, metricsPayloads [][]map[string]interface{}, duplicationPayloads [][]map[string]interface{}, commitUUID string, projectToken string, apiToken string) error {
+	if projectToken != "" {
 		for _, payload := range payloads {
-			sendResultsWithAPIToken(payload, commitUUID, apiToken, provider, owner, repository)
+			if err := sendResultsWithProjectToken(client, payload, commitUUID, projectToken); err != nil {
+				return err
+			}
+		}
+		for _, payload := range metricsPayloads {
+			if err := sendMetricsWithProjectToken(client, payload, commitUUID, projectToken); err != nil {
+				return err
+			}
 		}
-		resultsFinalWithAPIToken(commitUUID, apiToken, provider, owner, repository)
+		for _, payload := range duplicationPayloads {
+			if err := sendDuplicationWithProjectToken(client, payload, commitUUID, projectToken); err != nil {
+				return err
+			}
+		}
+		return resultsFinalWithProjectToken(client, commitUUID, projectToken)
 	}
 
+	for _, payload := range payloads {
+		if err := sendResultsWithAPIToken(client, payload, commitUUID, apiToken, provider, owner, repository); err != nil {
+			return err
+		}
+	}
+	for _, payload := range metricsPayloads {
+		if err := sendMetricsWithAPIToken(client, payload, commitUUID, apiToken, provider, owner, repository); err != nil {
+			return err
+		}
+	}
+	for _, payload := range duplicationPayloads {
+		if err := sendDuplicationWithAPIToken(client, payload, commitUUID, apiToken, provider, owner, repository); err != nil {
+			return err
+		}
+	}
+	return resultsFinalWithAPIToken(client, commitUUID, apiToken, provider, owner, repository)
 }
 
-func processSarif(sarif Sarif, tools map[string]*plugins.ToolInfo) [][]map[string]interface{} {
-	var codacyIssues []map[string]interface{}
+// processSarif builds the payloads sending the results of each tool to Codacy, and the rules of each tool
+// mapped to Codacy patterns. Rules without a direct Codacy pattern use the pattern set in ruleMappings, if any,
+// and their issues are sent as issues of the tool of that pattern, so tools unknown to Codacy can be uploaded.
+// It fails when the patterns of a tool can't be looked up, instead of leaving out its issues.
+func processSarif(toolResults []domain.ToolResults, tools map[string]*plugins.ToolInfo, lookupToolPatterns toolPatternsLookup, lookupPattern patternLookup, ruleMappings *userRuleMappings) ([][]map[string]interface{}, []*toolRuleMappings, error) {
 	var payloads [][]map[string]interface{}
+	var mappings []*toolRuleMappings
+	lookupPattern = mappedPatternLookup(lookupPattern)
 
-	baseDir, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("Error getting current working directory: %v\n", err)
-		os.Exit(1)
-	}
-
-	for _, run := range sarif.Runs {
+	for _, run := range toolResults {
 		//getToolName will take care of mapping sarif tool names to codacy tool names
 		//especially for eslint and pmd that have multiple versions
-		var toolName = getToolName(strings.ToLower(run.Tool.Driver.Name), run.Tool.Driver.Version)
-		tool, patterns := loadsToolAndPatterns(toolName, false)
-
-		for _, result := range run.Results {
-			modifiedType := tool.Prefix + strings.Replace(result.RuleID, "/", "_", -1)
-			pattern := getPatternByID(patterns, modifiedType)
+		var toolName = getToolName(strings.ToLower(run.Tool), run.Version)
+		tool, patterns, err := lookupToolPatterns(toolName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load the Codacy patterns of %s: %w", toolName, err)
+		}
+		toolMappings := newToolRuleMappings(getToolShortName(toolName))
+		mappings = append(mappings, toolMappings)
+
+		// Each payload only has the issues of its own tool, which is the tool of their pattern
+		codacyIssuesByTool := make(map[string][]map[string]interface{})
+		for _, result := range run.Issues {
+			// Suppressed issues are kept in the SARIF for audits, but are not reported
+			if result.Suppressed {
+				continue
+			}
+			patternTool, pattern, err := mapRuleToPattern(patterns, tool, []string{run.Tool, toolName}, result.PatternID, ruleMappings, lookupPattern)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to load the Codacy pattern of rule %s of %s: %w", result.PatternID, toolName, err)
+			}
 			if pattern == nil {
-				fmt.Printf("Rule '%s' doesn't have a direct mapping on Codacy\n", modifiedType)
+				toolMappings.add(result.PatternID, "")
 				continue
 			}
-			for _, location := range result.Locations {
-
-				fullURI := location.PhysicalLocation.ArtifactLocation.URI
-				relativePath := getRelativePath(baseDir, fullURI)
+			// Issues that are not located in a file can't be uploaded
+			locations := result.Locations()
+			if len(locations) == 0 {
+				continue
+			}
+			toolMappings.add(result.PatternID, pattern.ID)
+			if patternTool == "" {
+				patternTool = toolName
+			}
 
+			// Codacy issues have a single line, so an issue is sent at each of its locations
+			codacyIssue := newCodacyIssue(result, pattern)
+			for _, location := range locations {
 				issue := map[string]interface{}{
-					"source":   relativePath,
-					"line":     location.PhysicalLocation.Region.StartLine,
-					"type":     pattern.ID,
-					"message":  result.Message.Text,
-					"level":    pattern.Level,
-					"category": pattern.Category,
+					"source":   location.Path,
+					"line":     location.Region.StartLine,
+					"type":     codacyIssue.Type,
+					"message":  codacyIssue.Message,
+					"level":    codacyIssue.Level,
+					"category": codacyIssue.Category,
 				}
 
 				// Only add sourceId for tools that need it
-				if toolInfo, exists := tools[toolName]; exists && toolInfo.NeedsSourceIDUpload {
-					issue["sourceId"] = result.RuleID
+				if toolInfo, exists := tools[patternTool]; exists && toolInfo.NeedsSourceIDUpload {
+					issue["sourceId"] = result.PatternID
 				}
 
-				codacyIssues = append(codacyIssues, issue)
+				codacyIssuesByTool[patternTool] = append(codacyIssuesByTool[patternTool], issue)
 			}
 		}
-		var results []map[string]interface{}
-		// Iterate through run.Artifacts and create entries in the results object
-		for _, artifact := range run.Artifacts {
-			if artifact.Location.URI != "" {
-
-				fullURI := artifact.Location.URI
-				relativePath := getRelativePath(baseDir, fullURI)
-
-				results = append(results, map[string]interface{}{
-					"filename": relativePath,
-					"results":  []map[string]interface{}{},
-				})
+
+		// Tools unknown to Codacy only send the issues mapped to patterns of other tools
+		var payloadTools []string
+		if tool.Name != "" {
+			payloadTools = append(payloadTools, toolName)
+		}
+		for _, patternTool := range sortedKeys(codacyIssuesByTool) {
+			if patternTool != toolName {
+				payloadTools = append(payloadTools, patternTool)
 			}
 		}
-		for _, obj := range codacyIssues {
-			source := obj["source"].(string)
-			issue := map[string]interface{}{
-				"patternId": map[string]string{
-					"value": obj["type"].(string),
-				},
-				"filename": source,
-				"message": map[string]string{
-					"text": obj["message"].(string),
-				},
-				"level": obj["level"].(string),
-				//"category": obj["category"].(string),
-				"location": map[string]interface{}{
-					"LineLocation": map[string]int{
-						"line": obj["line"].(int),
-					},
-				},
+		for _, payloadTool := range payloadTools {
+			var files []string
+			if payloadTool == toolName {
+				files = run.Files
 			}
+			toolInfo, exists := tools[payloadTool]
+			results := groupCodacyIssuesByFile(files, codacyIssuesByTool[payloadTool], exists && toolInfo.NeedsSourceIDUpload)
+			toolPayloads := chunkToolPayload(getToolShortName(payloadTool), results, maxUploadChunkBytes)
+			toolMappings.Requests += len(toolPayloads)
+			payloads = append(payloads, toolPayloads...)
+		}
+	}
 
-			// Only add sourceId for tools that need it
-			if toolInfo, exists := tools[toolName]; exists && toolInfo.NeedsSourceIDUpload {
-				issue["sourceId"] = obj["sourceId"].(string)
-			}
+	return payloads, mappings, nil
+}
 
-			// Check if we already have an entry for this filename
-			found := false
-			for i, result := range results {
-				if result["filename"] == source {
-					// If we do, append this issue to its results
-					results[i]["results"] = append(results[i]["results"].([]map[string]interface{}), map[string]interface{}{"Issue": issue})
-					found = true
-					break
-				}
-			}
+// groupCodacyIssuesByFile builds the results of a tool payload: an entry for each analyzed file, and the issues
+// of each file
+func groupCodacyIssuesByFile(files []string, codacyIssues []map[string]interface{}, needsSourceID bool) []map[string]interface{} {
+	var results []map[string]interface{}
+	// Create entries in the results object for the files the tool analyzed
+	for _, file := range files {
+		results = append(results, map[string]interface{}{
+			"filename": file,
+			"results":  []map[string]interface{}{},
+		})
+	}
+	for _, obj := range codacyIssues {
+		source := obj["source"].(string)
+		issue := map[string]interface{}{
+			"patternId": map[string]string{
+				"value": obj["type"].(string),
+			},
+			"filename": source,
+			"message": map[string]string{
+				"text": obj["message"].(string),
+			},
+			"level": obj["level"].(string),
+			//"category": obj["category"].(string),
+			"location": map[string]interface{}{
+				"LineLocation": map[string]int{
+					"line": obj["line"].(int),
+				},
+			},
+		}
+
+		// Only add sourceId for tools that need it
+		if needsSourceID {
+			issue["sourceId"] = obj["sourceId"].(string)
+		}
 
-			// If we don't, create a new entry
-			if !found {
-				results = append(results, map[string]interface{}{
-					"filename": source,
-					"results":  []map[string]interface{}{{"Issue": issue}},
-				})
+		// Check if we already have an entry for this filename
+		found := false
+		for i, result := range results {
+			if result["filename"] == source {
+				// If we do, append this issue to its results
+				results[i]["results"] = append(results[i]["results"].([]map[string]interface{}), map[string]interface{}{"Issue": issue})
+				found = true
+				break
 			}
+		}
 
+		// If we don't, create a new entry
+		if !found {
+			results = append(results, map[string]interface{}{
+				"filename": source,
+				"results":  []map[string]interface{}{{"Issue": issue}},
+			})
 		}
-		var toolShortName = getToolShortName(toolName)
-		payload := []map[string]interface{}{
-			{
-				"tool": toolShortName,
-				"issues": map[string]interface{}{
-					"Success": map[string]interface{}{
-						"results": results,
-					},
+
+	}
+	return results
+}
+
+// newToolPayload builds the payload sending the results of a tool, grouped by file
+func newToolPayload(toolShortName string, results []map[string]interface{}) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"tool": toolShortName,
+			"issues": map[string]interface{}{
+				"Success": map[string]interface{}{
+					"results": results,
 				},
 			},
-		}
-		payloads = append(payloads, payload)
+		},
 	}
+}
+
+// chunkToolPayload splits the results of a tool into payloads of at most maxBytes once marshaled. The results
+// of a file are kept together, so a file whose results alone exceed maxBytes is sent in a payload of its own.
+func chunkToolPayload(toolShortName string, results []map[string]interface{}, maxBytes int) [][]map[string]interface{} {
+	return chunkPayload(results, maxBytes, func(results []map[string]interface{}) []map[string]interface{} {
+		return newToolPayload(toolShortName, results)
+	})
+}
+
+// chunkPayload splits results, one per file, into the payloads built by newPayload of at most maxBytes once
+// marshaled. A file whose results alone exceed maxBytes is sent in a payload of its own.
+func chunkPayload(results []map[string]interface{}, maxBytes int, newPayload func(results []map[string]interface{}) []map[string]interface{}) [][]map[string]interface{} {
+	emptyPayload, _ := json.Marshal(newPayload([]map[string]interface{}{}))
 
+	var payloads [][]map[string]interface{}
+	var chunk []map[string]interface{}
+	chunkBytes := len(emptyPayload)
+	for _, result := range results {
+		resultBytes, _ := json.Marshal(result)
+		// The results are separated by commas
+		size := len(resultBytes) + 1
+		if len(chunk) > 0 && chunkBytes+size > maxBytes {
+			payloads = append(payloads, newPayload(chunk))
+			chunk, chunkBytes = nil, len(emptyPayload)
+		}
+		chunk = append(chunk, result)
+		chunkBytes += size
+	}
+	// Tools without results still send a payload, so Codacy knows they ran
+	if len(chunk) > 0 || len(payloads) == 0 {
+		payloads = append(payloads, newPayload(chunk))
+	}
 	return payloads
 }
 
-func resultsFinalWithProjectToken(commitUUID string, projectToken string) {
-	url := fmt.Sprintf("https://api.codacy.com/2.0/commit/%s/resultsFinal", commitUUID)
-	req, _ := http.NewRequest("POST", url, nil)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("project-token", projectToken)
+func resultsFinalWithProjectToken(client *uploadClient, commitUUID string, projectToken string) error {
+	url := fmt.Sprintf("%s/2.0/commit/%s/resultsFinal", uploadAPIBase(), commitUUID)
+	return sendResultsFinal(client, url, map[string]string{"project-token": projectToken})
+}
+
+func resultsFinalWithAPIToken(client *uploadClient, commitUUID string, apiToken string, provider string, owner string, repository string) error {
+	url := fmt.Sprintf("%s/2.0/%s/%s/%s/commit/%s/resultsFinal", uploadAPIBase(), provider, owner, repository, commitUUID)
+	return sendResultsFinal(client, url, map[string]string{"api-token": apiToken})
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// sendResultsFinal tells Codacy that all the results of the commit were sent
+func sendResultsFinal(client *uploadClient, url string, headers map[string]string) error {
+	body, err := client.post(url, headers, nil)
 	if err != nil {
-		fmt.Println("Error:", err)
-		return
+		return fmt.Errorf("error finishing the upload: %w", err)
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	fmt.Println("Response:", resp.Status)
 	fmt.Println("Response Body:", string(body))
+	return nil
 }
 
-func resultsFinalWithAPIToken(commitUUID string, apiToken string, provider string, owner string, repository string) {
-	url := fmt.Sprintf("https://api.codacy.com/2.0/%s/%s/%s/commit/%s/resultsFinal", provider, owner, repository, commitUUID)
-	req, _ := http.NewRequest("POST", url, nil)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("api-token", apiToken)
+// codacyPatternID is the id of the Codacy pattern of a rule reported by a tool: the rule id prefixed with the
+// tool prefix, with slashes replaced by underscores
+func codacyPatternID(tool domain.Tool, ruleID string) string {
+	return tool.Prefix + strings.Replace(ruleID, "/", "_", -1)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return
+// newCodacyIssue builds the issue Codacy shows for an issue reported by a tool, given the pattern of its rule
+func newCodacyIssue(issue domain.Issue, pattern *domain.SarifPatternConfiguration) CodacyIssue {
+	return CodacyIssue{
+		Source:   issue.Path,
+		Line:     issue.Region.StartLine,
+		Type:     pattern.ID,
+		Message:  issue.Message,
+		Level:    pattern.Level,
+		Category: pattern.Category,
+	}
+}
+
+// mapRuleToPattern finds the Codacy pattern of a rule of a tool, known by toolNames. Rules without a pattern of
+// their own use the pattern set in ruleMappings, looked up by its exact id among the patterns of every Codacy tool,
+// and patternTool is then the name of the Codacy tool of that pattern.
+func mapRuleToPattern(patterns []domain.PatternConfiguration, tool domain.Tool, toolNames []string, ruleID string, ruleMappings *userRuleMappings, lookupPattern patternLookup) (patternTool string, pattern *domain.SarifPatternConfiguration, err error) {
+	if pattern := getPatternByID(patterns, codacyPatternID(tool, ruleID)); pattern != nil {
+		return "", pattern, nil
+	}
+	patternID := ruleMappings.patternID(toolNames, ruleID)
+	if patternID == "" {
+		return "", nil, nil
+	}
+	mappedTool, definition, err := lookupPattern(patternID)
+	if err != nil || definition == nil {
+		return "", nil, err
+	}
+	mappedPattern := newSarifPatternConfiguration(*definition)
+	return mappedTool.Name, &mappedPattern, nil
+}
+
+func newSarifPatternConfiguration(definition domain.PatternDefinition) domain.SarifPatternConfiguration {
+	return domain.SarifPatternConfiguration{
+		UUID:        definition.Id,
+		ID:          definition.Id,
+		Category:    definition.Category,
+		Description: definition.Description,
+		Level:       definition.Level,
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	fmt.Println("Response:", resp.Status)
-	fmt.Println("Response Body:", string(body))
 }
 
 func getPatternByID(patterns []domain.PatternConfiguration, patternID string) *domain.SarifPatternConfiguration {
 	var sarifPatterns []domain.SarifPatternConfiguration
 	for _, p := range patterns {
-		sarifPatterns = append(sarifPatterns, domain.SarifPatternConfiguration{
-			UUID:        p.PatternDefinition.Id,
-			ID:          p.PatternDefinition.Id,
-			Category:    p.PatternDefinition.Category,
-			Description: p.PatternDefinition.Description,
-			Level:       p.PatternDefinition.Level,
-		})
+		sarifPatterns = append(sarifPatterns, newSarifPatternConfiguration(p.PatternDefinition))
 	}
 	for _, p := range sarifPatterns {
 		//Only for PMD v6 or PMD v7 because the patternID is only part
@@ -322,67 +475,27 @@ func getMajorVersion(version string) int {
 	return -1
 }
 
-func sendResultsWithProjectToken(payload []map[string]interface{}, commitUUID string, projectToken string) {
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		fmt.Printf("Error marshaling payload: %v\n", err)
-		panic("panic")
-	}
-
-	url := fmt.Sprintf("https://api.codacy.com/2.0/commit/%s/issuesRemoteResults", commitUUID)
-	fmt.Printf("Sending results to URL: %s\n", url)
-	fmt.Println("Payload:", string(payloadBytes))
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		os.Exit(1)
-	}
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("project-token", projectToken)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending results: %v\n", err)
-		os.Exit(1)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error sending results, status code: %d\n", resp.StatusCode)
-		os.Exit(1)
-	}
+func sendResultsWithProjectToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, projectToken string) error {
+	url := fmt.Sprintf("%s/2.0/commit/%s/issuesRemoteResults", uploadAPIBase(), commitUUID)
+	return sendResults(client, url, map[string]string{"project-token": projectToken}, payload)
 }
 
-func sendResultsWithAPIToken(payload []map[string]interface{}, commitUUID string, apiToken string, provider string, owner string, repository string) {
+func sendResultsWithAPIToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, apiToken string, provider string, owner string, repository string) error {
+	url := fmt.Sprintf("%s/2.0/%s/%s/%s/commit/%s/issuesRemoteResults", uploadAPIBase(), provider, owner, repository, commitUUID)
+	return sendResults(client, url, map[string]string{"api-token": apiToken}, payload)
+}
 
+// sendResults sends a payload with the results of a tool
+func sendResults(client *uploadClient, url string, headers map[string]string, payload []map[string]interface{}) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Printf("Error marshaling payload: %v\n", err)
-		panic("panic")
+		return fmt.Errorf("error marshaling payload: %w", err)
 	}
 
-	url := fmt.Sprintf("https://api.codacy.com/2.0/%s/%s/%s/commit/%s/issuesRemoteResults", provider, owner, repository, commitUUID)
 	fmt.Printf("Sending results to URL: %s\n", url)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		os.Exit(1)
-	}
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("api-token", apiToken)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending results: %v\n", err)
-		os.Exit(1)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error sending results, status code: %d\n", resp.StatusCode)
-		os.Exit(1)
-	} else {
-		fmt.Println("Results sent successfully")
+	if _, err := client.post(url, headers, payloadBytes); err != nil {
+		return fmt.Errorf("error sending results: %w", err)
 	}
+	fmt.Println("Results sent successfully")
+	return nil
 }
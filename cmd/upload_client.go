@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultUploadTimeout is the maximum time of each upload request
+	defaultUploadTimeout = 2 * time.Minute
+	// defaultUploadRetries is the number of times a failed upload request is retried
+	defaultUploadRetries = 3
+	// uploadInitialBackoff is the wait before the first retry, doubled on every retry up to uploadMaxBackoff
+	uploadInitialBackoff = time.Second
+	uploadMaxBackoff     = 30 * time.Second
+)
+
+// uploadClient sends analysis results to Codacy, retrying the requests that fail temporarily
+type uploadClient struct {
+	httpClient     httpDoer
+	retries        int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	sleep          func(time.Duration)
+}
+
+// newUploadClient creates an uploadClient whose requests time out after timeout and are retried retries times
+func newUploadClient(timeout time.Duration, retries int) *uploadClient {
+	return &uploadClient{
+		httpClient:     &http.Client{Timeout: timeout},
+		retries:        retries,
+		initialBackoff: uploadInitialBackoff,
+		maxBackoff:     uploadMaxBackoff,
+		sleep:          time.Sleep,
+	}
+}
+
+// post sends a JSON body and returns the response body. Requests are retried when the connection to the server
+// fails, when they are rate limited (429), or when the server fails (5xx). Timeouts may happen after the server
+// processed a request, so they aren't retried. Retries wait with exponential backoff, or as long as a Retry-After
+// header asks.
+func (c *uploadClient) post(url string, headers map[string]string, body []byte) ([]byte, error) {
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		responseBody, retryAfter, err := c.postOnce(url, headers, body)
+		if err == nil {
+			return responseBody, nil
+		}
+		if retryAfter < 0 || attempt >= c.retries {
+			return nil, err
+		}
+
+		wait := max(backoff, retryAfter)
+		log.Printf("Retrying in %s (%d/%d): %v", wait, attempt+1, c.retries, err)
+		c.sleep(wait)
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// postOnce sends a request once. When it fails, retryAfter is the wait the server asked for before retrying,
+// 0 if it didn't ask for any, or -1 when the request must not be retried.
+func (c *uploadClient) postOnce(url string, headers map[string]string, body []byte) ([]byte, time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("content-type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("error sending request to %s: %w", url, err)
+		if !isConnectionError(err) {
+			return nil, -1, err
+		}
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to read response of %s: %w", url, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return responseBody, 0, nil
+	}
+
+	err = fmt.Errorf("request to %s failed with status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(responseBody)))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), err
+	}
+	return nil, -1, err
+}
+
+// isConnectionError tells if a request failed because the connection to the server couldn't be opened, so the
+// request wasn't sent
+func isConnectionError(err error) bool {
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) {
+		return true
+	}
+	var opError *net.OpError
+	return errors.As(err, &opError) && opError.Op == "dial"
+}
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds or an HTTP date.
+// Missing or invalid headers ask for no wait.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
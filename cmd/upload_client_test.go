@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestUploadClient creates an uploadClient that records its waits instead of sleeping
+func newTestUploadClient(retries int, waits *[]time.Duration) *uploadClient {
+	client := newUploadClient(time.Second, retries)
+	client.sleep = func(wait time.Duration) {
+		*waits = append(*waits, wait)
+	}
+	return client
+}
+
+func TestUploadClientRetriesWithBackoff(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"a":1}`, string(body))
+		assert.Equal(t, "token", r.Header.Get("project-token"))
+		assert.Equal(t, "application/json", r.Header.Get("content-type"))
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 4:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 5:
+			w.WriteHeader(http.StatusGatewayTimeout)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	body, err := newTestUploadClient(5, &waits).post(server.URL, map[string]string{"project-token": "token"}, []byte(`{"a":1}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, 6, requests)
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}, waits)
+}
+
+func TestUploadClientGivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	_, err := newTestUploadClient(2, &waits).post(server.URL, nil, nil)
+
+	assert.ErrorContains(t, err, "failed with status 503: boom")
+	assert.Equal(t, 3, requests)
+	assert.Len(t, waits, 2)
+}
+
+func TestUploadClientDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(status)
+		}))
+
+		var waits []time.Duration
+		_, err := newTestUploadClient(3, &waits).post(server.URL, nil, nil)
+		server.Close()
+
+		assert.ErrorContains(t, err, fmt.Sprintf("failed with status %d", status))
+		assert.Equal(t, 1, requests, status)
+		assert.Empty(t, waits, status)
+	}
+}
+
+func TestUploadClientDoesNotRetryTimeouts(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	client := newTestUploadClient(3, &waits)
+	client.httpClient = &http.Client{Timeout: 50 * time.Millisecond}
+	_, err := client.post(server.URL, nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Empty(t, waits)
+}
+
+func TestUploadClientRetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	var waits []time.Duration
+	_, err := newTestUploadClient(2, &waits).post(url, nil, nil)
+
+	assert.ErrorContains(t, err, "error sending request")
+	assert.Len(t, waits, 2)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 7*time.Second, parseRetryAfter("7", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Wed, 01 Jan 2025 12:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 01 Jan 2025 11:00:00 GMT", now), "dates in the past")
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-3", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"codacy/cli-v2/constants"
+)
+
+// toolRuleMappings records the Codacy pattern each rule of a tool was mapped to when building the upload payloads
+type toolRuleMappings struct {
+	// Tool is the Codacy short name of the tool
+	Tool string
+	// Requests is the number of requests sending the results of the tool
+	Requests int
+	// rules maps the rule ids reported by the tool to their Codacy pattern, empty when the rule has none
+	rules map[string]*ruleMapping
+}
+
+type ruleMapping struct {
+	patternID string
+	issues    int
+}
+
+func newToolRuleMappings(tool string) *toolRuleMappings {
+	return &toolRuleMappings{Tool: tool, rules: make(map[string]*ruleMapping)}
+}
+
+// add records an issue of a rule mapped to patternID, or not mapped when patternID is empty
+func (m *toolRuleMappings) add(ruleID string, patternID string) {
+	mapping, ok := m.rules[ruleID]
+	if !ok {
+		mapping = &ruleMapping{patternID: patternID}
+		m.rules[ruleID] = mapping
+	}
+	mapping.issues++
+}
+
+// mappedIssues is the number of issues sent to Codacy
+func (m *toolRuleMappings) mappedIssues() int {
+	issues := 0
+	for _, mapping := range m.rules {
+		if mapping.patternID != "" {
+			issues += mapping.issues
+		}
+	}
+	return issues
+}
+
+// writeUploadPayloads writes the request bodies sent to Codacy as a JSON array, one element per request
+func writeUploadPayloads(payloads [][]map[string]interface{}, outputFile string) error {
+	if payloads == nil {
+		payloads = [][]map[string]interface{}{}
+	}
+	data, err := json.MarshalIndent(payloads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling payloads: %w", err)
+	}
+	if err := os.WriteFile(outputFile, append(data, '\n'), constants.DefaultFilePerms); err != nil {
+		return fmt.Errorf("error writing payloads: %w", err)
+	}
+	return nil
+}
+
+// printRuleMappings prints the rules of each tool that were mapped to Codacy patterns and the ones that were not
+func printRuleMappings(w io.Writer, mappings []*toolRuleMappings, requests int) {
+	fmt.Fprintf(w, "Dry run: %d request(s) built, nothing was sent to Codacy\n", requests)
+	for _, toolMappings := range mappings {
+		fmt.Fprintf(w, "\n%s: %d issue(s) in %d request(s)\n", toolMappings.Tool, toolMappings.mappedIssues(), toolMappings.Requests)
+
+		ruleIDs := make([]string, 0, len(toolMappings.rules))
+		for ruleID := range toolMappings.rules {
+			ruleIDs = append(ruleIDs, ruleID)
+		}
+		sort.Strings(ruleIDs)
+
+		var mapped, unmapped []string
+		for _, ruleID := range ruleIDs {
+			mapping := toolMappings.rules[ruleID]
+			if mapping.patternID == "" {
+				unmapped = append(unmapped, fmt.Sprintf("%s (%d)", ruleID, mapping.issues))
+			} else {
+				mapped = append(mapped, fmt.Sprintf("%s -> %s (%d)", ruleID, mapping.patternID, mapping.issues))
+			}
+		}
+		for _, section := range []struct {
+			title string
+			rules []string
+		}{{"Mapped rules", mapped}, {"Unmapped rules, not sent", unmapped}} {
+			if len(section.rules) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "  %s:\n", section.title)
+			for _, rule := range section.rules {
+				fmt.Fprintf(w, "    %s\n", rule)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintRuleMappings(t *testing.T) {
+	eslint := newToolRuleMappings("eslint-8")
+	eslint.add("semi", "ESLint8_semi")
+	eslint.add("semi", "ESLint8_semi")
+	eslint.add("react/jsx-key", "ESLint8_react_jsx-key")
+	eslint.add("custom/rule", "")
+	eslint.Requests = 2
+	trivy := newToolRuleMappings("trivy")
+	trivy.Requests = 1
+
+	var output bytes.Buffer
+	printRuleMappings(&output, []*toolRuleMappings{eslint, trivy}, 3)
+
+	assert.Equal(t, "Dry run: 3 request(s) built, nothing was sent to Codacy\n"+
+		"\n"+
+		"eslint-8: 3 issue(s) in 2 request(s)\n"+
+		"  Mapped rules:\n"+
+		"    react/jsx-key -> ESLint8_react_jsx-key (1)\n"+
+		"    semi -> ESLint8_semi (2)\n"+
+		"  Unmapped rules, not sent:\n"+
+		"    custom/rule (1)\n"+
+		"\n"+
+		"trivy: 0 issue(s) in 1 request(s)\n", output.String())
+}
+
+func TestWriteUploadPayloads(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "payload.json")
+
+	require.NoError(t, writeUploadPayloads(nil, outputFile))
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(content))
+
+	payloads := [][]map[string]interface{}{newToolPayload("trivy", nil), newToolPayload("eslint-8", []map[string]interface{}{{"filename": "a.js"}})}
+	require.NoError(t, writeUploadPayloads(payloads, outputFile))
+	content, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		[{"tool": "trivy", "issues": {"Success": {"results": null}}}],
+		[{"tool": "eslint-8", "issues": {"Success": {"results": [{"filename": "a.js"}]}}}]
+	]`, string(content))
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codacy/cli-v2/domain"
+)
+
+// codacyLanguagesByExtension maps the extensions of the files Lizard analyzes to the names of their Codacy language
+var codacyLanguagesByExtension = map[string]string{
+	".c":     "C",
+	".h":     "C",
+	".cc":    "CPP",
+	".cpp":   "CPP",
+	".cxx":   "CPP",
+	".hpp":   "CPP",
+	".cs":    "CSharp",
+	".erl":   "Erlang",
+	".f90":   "Fortran",
+	".go":    "Go",
+	".java":  "Java",
+	".js":    "Javascript",
+	".jsx":   "Javascript",
+	".mjs":   "Javascript",
+	".kt":    "Kotlin",
+	".kts":   "Kotlin",
+	".lua":   "Lua",
+	".m":     "ObjectiveC",
+	".php":   "PHP",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".scala": "Scala",
+	".sol":   "Solidity",
+	".swift": "Swift",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+}
+
+// processMetrics builds the payloads sending the file metrics reported by the tools to Codacy, by language, split
+// like the results so each payload stays under maxUploadChunkBytes. Files of languages unknown to Codacy are left out.
+func processMetrics(toolResults []domain.ToolResults) [][]map[string]interface{} {
+	resultsByLanguage := make(map[string][]map[string]interface{})
+	seen := make(map[string]bool)
+	skipped := 0
+	for _, run := range toolResults {
+		for _, metrics := range run.Metrics {
+			if seen[metrics.Path] {
+				continue
+			}
+			seen[metrics.Path] = true
+
+			language, ok := codacyLanguagesByExtension[strings.ToLower(filepath.Ext(metrics.Path))]
+			if !ok {
+				skipped++
+				continue
+			}
+			lineComplexities := metrics.LineComplexities
+			if lineComplexities == nil {
+				lineComplexities = []domain.LineComplexity{}
+			}
+			resultsByLanguage[language] = append(resultsByLanguage[language], map[string]interface{}{
+				"filename":         metrics.Path,
+				"loc":              metrics.Nloc,
+				"complexity":       metrics.Ccn,
+				"nrMethods":        metrics.Functions,
+				"lineComplexities": lineComplexities,
+			})
+		}
+	}
+	if skipped > 0 {
+		log.Printf("Metrics of %d file(s) left out, as their language is not known to Codacy", skipped)
+	}
+
+	var payloads [][]map[string]interface{}
+	for _, language := range sortedKeys(resultsByLanguage) {
+		results := resultsByLanguage[language]
+		sort.Slice(results, func(i, j int) bool {
+			return results[i]["filename"].(string) < results[j]["filename"].(string)
+		})
+		payloads = append(payloads, chunkPayload(results, maxUploadChunkBytes, func(results []map[string]interface{}) []map[string]interface{} {
+			return newMetricsPayload(language, results)
+		})...)
+	}
+	return payloads
+}
+
+// newMetricsPayload builds the payload sending the file metrics of a language
+func newMetricsPayload(language string, results []map[string]interface{}) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"language": language,
+			"metrics": map[string]interface{}{
+				"Success": map[string]interface{}{
+					"results": results,
+				},
+			},
+		},
+	}
+}
+
+// printMetricsSummary prints the number of files whose metrics would be sent for each language
+func printMetricsSummary(w io.Writer, metricsPayloads [][]map[string]interface{}) {
+	var languages []string
+	files := make(map[string]int)
+	for _, payload := range metricsPayloads {
+		for _, languageMetrics := range payload {
+			language := languageMetrics["language"].(string)
+			if _, ok := files[language]; !ok {
+				languages = append(languages, language)
+			}
+			files[language] += len(languageMetrics["metrics"].(map[string]interface{})["Success"].(map[string]interface{})["results"].([]map[string]interface{}))
+		}
+	}
+	for _, language := range languages {
+		fmt.Fprintf(w, "\nMetrics of %s: %d file(s)\n", language, files[language])
+	}
+}
+
+// processDuplication builds the payloads sending the duplicated blocks reported by the tools to Codacy, by the
+// language of their first location, split like the results so each payload stays under maxUploadChunkBytes.
+// Blocks are reported in the metrics of each of their files, and are sent once.
+func processDuplication(toolResults []domain.ToolResults) [][]map[string]interface{} {
+	blocksByLanguage := make(map[string]map[string]domain.DuplicatedBlock)
+	for _, run := range toolResults {
+		for _, metrics := range run.Metrics {
+			for _, block := range metrics.Duplication {
+				if len(block.Locations) == 0 {
+					continue
+				}
+				language, ok := codacyLanguagesByExtension[strings.ToLower(filepath.Ext(block.Locations[0].Path))]
+				if !ok {
+					continue
+				}
+				if blocksByLanguage[language] == nil {
+					blocksByLanguage[language] = make(map[string]domain.DuplicatedBlock)
+				}
+				blocksByLanguage[language][duplicatedBlockKey(block)] = block
+			}
+		}
+	}
+
+	var payloads [][]map[string]interface{}
+	for _, language := range sortedKeys(blocksByLanguage) {
+		blocks := blocksByLanguage[language]
+		var results []map[string]interface{}
+		for _, key := range sortedKeys(blocks) {
+			block := blocks[key]
+			files := make([]map[string]interface{}, 0, len(block.Locations))
+			for _, location := range block.Locations {
+				files = append(files, map[string]interface{}{
+					"filePath":  location.Path,
+					"startLine": location.StartLine,
+					"endLine":   location.EndLine,
+				})
+			}
+			results = append(results, map[string]interface{}{
+				"cloneLines": block.Lines,
+				// Lizard doesn't report the number of tokens of the blocks
+				"nrTokens": 0,
+				"nrLines":  block.Locations[0].EndLine - block.Locations[0].StartLine + 1,
+				"files":    files,
+			})
+		}
+		payloads = append(payloads, chunkPayload(results, maxUploadChunkBytes, func(results []map[string]interface{}) []map[string]interface{} {
+			return newDuplicationPayload(language, results)
+		})...)
+	}
+	return payloads
+}
+
+// duplicatedBlockKey identifies a duplicated block by its locations
+func duplicatedBlockKey(block domain.DuplicatedBlock) string {
+	var key strings.Builder
+	for _, location := range block.Locations {
+		fmt.Fprintf(&key, "%s:%d:%d;", location.Path, location.StartLine, location.EndLine)
+	}
+	return key.String()
+}
+
+// newDuplicationPayload builds the payload sending the duplicated blocks of a language
+func newDuplicationPayload(language string, results []map[string]interface{}) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"language": language,
+			"duplication": map[string]interface{}{
+				"Success": map[string]interface{}{
+					"results": results,
+				},
+			},
+		},
+	}
+}
+
+// printDuplicationSummary prints the number of duplicated blocks that would be sent for each language
+func printDuplicationSummary(w io.Writer, duplicationPayloads [][]map[string]interface{}) {
+	var languages []string
+	blocks := make(map[string]int)
+	for _, payload := range duplicationPayloads {
+		for _, languageDuplication := range payload {
+			language := languageDuplication["language"].(string)
+			if _, ok := blocks[language]; !ok {
+				languages = append(languages, language)
+			}
+			blocks[language] += len(languageDuplication["duplication"].(map[string]interface{})["Success"].(map[string]interface{})["results"].([]map[string]interface{}))
+		}
+	}
+	for _, language := range languages {
+		fmt.Fprintf(w, "\nDuplication of %s: %d block(s)\n", language, blocks[language])
+	}
+}
+
+func sendMetricsWithProjectToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, projectToken string) error {
+	url := fmt.Sprintf("%s/2.0/commit/%s/metricsRemoteResults", uploadAPIBase(), commitUUID)
+	return sendResults(client, url, map[string]string{"project-token": projectToken}, payload)
+}
+
+func sendMetricsWithAPIToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, apiToken string, provider string, owner string, repository string) error {
+	url := fmt.Sprintf("%s/2.0/%s/%s/%s/commit/%s/metricsRemoteResults", uploadAPIBase(), provider, owner, repository, commitUUID)
+	return sendResults(client, url, map[string]string{"api-token": apiToken}, payload)
+}
+
+func sendDuplicationWithProjectToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, projectToken string) error {
+	url := fmt.Sprintf("%s/2.0/commit/%s/duplicationRemoteResults", uploadAPIBase(), commitUUID)
+	return sendResults(client, url, map[string]string{"project-token": projectToken}, payload)
+}
+
+func sendDuplicationWithAPIToken(client *uploadClient, payload []map[string]interface{}, commitUUID string, apiToken string, provider string, owner string, repository string) error {
+	url := fmt.Sprintf("%s/2.0/%s/%s/%s/commit/%s/duplicationRemoteResults", uploadAPIBase(), provider, owner, repository, commitUUID)
+	return sendResults(client, url, map[string]string{"api-token": apiToken}, payload)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMetrics(t *testing.T) {
+	toolResults := []domain.ToolResults{
+		{Tool: "ESLint", Issues: []domain.Issue{{PatternID: "semi", Path: "web/app.js"}}},
+		{Tool: "Lizard", Metrics: []domain.FileMetrics{
+			{Path: "src/b.py", Nloc: 10, Ccn: 4, MaxCcn: 3, Functions: 2, LineComplexities: []domain.LineComplexity{{Line: 1, Value: 3}, {Line: 6, Value: 1}}},
+			{Path: "src/a.py", Nloc: 3},
+			{Path: "web/app.js", Nloc: 20, Ccn: 1, MaxCcn: 1, Functions: 1, LineComplexities: []domain.LineComplexity{{Line: 2, Value: 1}}},
+			{Path: "src/a.py", Nloc: 99},
+			{Path: "templates/page.vue", Nloc: 5},
+		}},
+	}
+
+	payloads := processMetrics(toolResults)
+
+	data, err := json.Marshal(payloads)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		[{"language": "Javascript", "metrics": {"Success": {"results": [
+			{"filename": "web/app.js", "loc": 20, "complexity": 1, "nrMethods": 1, "lineComplexities": [{"line": 2, "value": 1}]}
+		]}}}],
+		[{"language": "Python", "metrics": {"Success": {"results": [
+			{"filename": "src/a.py", "loc": 3, "complexity": 0, "nrMethods": 0, "lineComplexities": []},
+			{"filename": "src/b.py", "loc": 10, "complexity": 4, "nrMethods": 2, "lineComplexities": [{"line": 1, "value": 3}, {"line": 6, "value": 1}]}
+		]}}}]
+	]`, string(data))
+
+	var out bytes.Buffer
+	printMetricsSummary(&out, payloads)
+	assert.Equal(t, "\nMetrics of Javascript: 1 file(s)\n\nMetrics of Python: 2 file(s)\n", out.String())
+}
+
+func TestProcessMetricsWithoutMetrics(t *testing.T) {
+	assert.Empty(t, processMetrics([]domain.ToolResults{{Tool: "ESLint"}}))
+}
+
+func TestProcessMetricsSplitsLargePayloads(t *testing.T) {
+	var metrics []domain.FileMetrics
+	for i := 0; i < 50000; i++ {
+		metrics = append(metrics, domain.FileMetrics{Path: fmt.Sprintf("src/module_%05d.py", i), Nloc: 100, Ccn: 10, Functions: 5})
+	}
+
+	payloads := processMetrics([]domain.ToolResults{{Tool: "Lizard", Metrics: metrics}})
+
+	require.Greater(t, len(payloads), 1)
+	for _, payload := range payloads {
+		payloadBytes, err := json.Marshal(payload)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(payloadBytes), maxUploadChunkBytes)
+	}
+	var out bytes.Buffer
+	printMetricsSummary(&out, payloads)
+	assert.Equal(t, "\nMetrics of Python: 50000 file(s)\n", out.String())
+}
+
+func TestProcessDuplication(t *testing.T) {
+	block := domain.DuplicatedBlock{Lines: "x = 1\ny = 2", Locations: []domain.DuplicatedLocation{
+		{Path: "src/a.py", StartLine: 3, EndLine: 4},
+		{Path: "src/b.py", StartLine: 10, EndLine: 11},
+	}}
+	toolResults := []domain.ToolResults{{Tool: "Lizard", Metrics: []domain.FileMetrics{
+		{Path: "src/a.py", Nloc: 4, Duplication: []domain.DuplicatedBlock{block}},
+		{Path: "src/b.py", Nloc: 11, Duplication: []domain.DuplicatedBlock{block}},
+		{Path: "templates/page.vue", Duplication: []domain.DuplicatedBlock{{Locations: []domain.DuplicatedLocation{
+			{Path: "templates/page.vue", StartLine: 1, EndLine: 5},
+			{Path: "templates/other.vue", StartLine: 1, EndLine: 5},
+		}}}},
+	}}}
+
+	payloads := processDuplication(toolResults)
+
+	data, err := json.Marshal(payloads)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		[{"language": "Python", "duplication": {"Success": {"results": [
+			{"cloneLines": "x = 1\ny = 2", "nrTokens": 0, "nrLines": 2, "files": [
+				{"filePath": "src/a.py", "startLine": 3, "endLine": 4},
+				{"filePath": "src/b.py", "startLine": 10, "endLine": 11}
+			]}
+		]}}}]
+	]`, string(data))
+
+	var out bytes.Buffer
+	printDuplicationSummary(&out, payloads)
+	assert.Equal(t, "\nDuplication of Python: 1 block(s)\n", out.String())
+	assert.Empty(t, processDuplication([]domain.ToolResults{{Tool: "ESLint"}}))
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"codacy/cli-v2/config"
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// userRuleMappings maps the rules without a direct Codacy pattern to Codacy patterns, as set in
+// .codacy/rule-mappings.yaml. Tools are keyed by their SARIF or Codacy name, ignoring case:
+//
+//	tools:
+//	  opengrep:
+//	    rules:
+//	      - rule: "acme.python.*"
+//	        pattern: Opengrep_python.lang.security.audit.eval-detected
+//	    fallback: Opengrep_generic
+type userRuleMappings struct {
+	Tools map[string]userToolRuleMappings `yaml:"tools"`
+}
+
+type userToolRuleMappings struct {
+	// Rules are checked in order, and the first one matching the rule id is used
+	Rules []userRuleMapping `yaml:"rules"`
+	// Fallback is the pattern of the rules not matching any of Rules
+	Fallback string `yaml:"fallback"`
+}
+
+type userRuleMapping struct {
+	// Rule is the rule id reported by the tool, or a path.Match glob such as "react/*"
+	Rule    string `yaml:"rule"`
+	Pattern string `yaml:"pattern"`
+}
+
+// ruleMappingsPath is the path of the rule mappings of the project
+func ruleMappingsPath() string {
+	return filepath.Join(config.Config.LocalCodacyDirectory(), constants.RuleMappingsFileName)
+}
+
+// loadRuleMappings reads and validates a rule mappings file. A missing file has no mappings.
+func loadRuleMappings(filePath string) (*userRuleMappings, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &userRuleMappings{}, nil
+		}
+		return nil, fmt.Errorf("failed to read rule mappings %s: %w", filePath, err)
+	}
+
+	var mappings userRuleMappings
+	if err := yaml.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse rule mappings %s: %w", filePath, err)
+	}
+	seen := make(map[string]bool)
+	for tool, toolMappings := range mappings.Tools {
+		if seen[strings.ToLower(tool)] {
+			return nil, fmt.Errorf("invalid rule mappings %s: %s is set more than once", filePath, tool)
+		}
+		seen[strings.ToLower(tool)] = true
+		for i, mapping := range toolMappings.Rules {
+			if mapping.Rule == "" || mapping.Pattern == "" {
+				return nil, fmt.Errorf("invalid rule mappings %s: rule %d of %s needs a rule and a pattern", filePath, i+1, tool)
+			}
+			if _, err := path.Match(mapping.Rule, ""); err != nil {
+				return nil, fmt.Errorf("invalid rule mappings %s: rule %q of %s: %w", filePath, mapping.Rule, tool, err)
+			}
+		}
+	}
+	return &mappings, nil
+}
+
+// patternID returns the Codacy pattern set for a rule of a tool, named by its SARIF driver name or its Codacy
+// name, or an empty string when none is set
+func (m *userRuleMappings) patternID(toolNames []string, ruleID string) string {
+	if m == nil {
+		return ""
+	}
+	for name, toolMappings := range m.Tools {
+		if !containsFold(toolNames, name) {
+			continue
+		}
+		for _, mapping := range toolMappings.Rules {
+			if matched, _ := path.Match(mapping.Rule, ruleID); matched {
+				return mapping.Pattern
+			}
+		}
+		return toolMappings.Fallback
+	}
+	return ""
+}
+
+// mappedPatternLookup looks up each pattern set in the rule mappings once, and warns about the patterns that
+// don't exist or can't be looked up, as their rules stay unmapped
+func mappedPatternLookup(lookup patternLookup) patternLookup {
+	type lookupResult struct {
+		tool    domain.Tool
+		pattern *domain.PatternDefinition
+		err     error
+	}
+	results := make(map[string]lookupResult)
+	return func(patternID string) (domain.Tool, *domain.PatternDefinition, error) {
+		result, ok := results[patternID]
+		if !ok {
+			result.tool, result.pattern, result.err = lookup(patternID)
+			if result.err != nil {
+				log.Printf("Warning: pattern %s set in %s not looked up: %v", patternID, constants.RuleMappingsFileName, result.err)
+			} else if result.pattern == nil {
+				log.Printf("Warning: pattern %s set in %s is not a Codacy pattern, its rules are not mapped", patternID, constants.RuleMappingsFileName)
+			}
+			results[patternID] = result
+		}
+		return result.tool, result.pattern, result.err
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// printUnmappedRules prints a table of the rules of each tool not mapped to a Codacy pattern, whose issues were
+// not sent. It prints nothing when every rule was mapped.
+func printUnmappedRules(w io.Writer, mappings []*toolRuleMappings) error {
+	type unmappedRule struct {
+		tool   string
+		rule   string
+		issues int
+	}
+	var unmapped []unmappedRule
+	for _, toolMappings := range mappings {
+		for ruleID, mapping := range toolMappings.rules {
+			if mapping.patternID == "" {
+				unmapped = append(unmapped, unmappedRule{toolMappings.Tool, ruleID, mapping.issues})
+			}
+		}
+	}
+	if len(unmapped) == 0 {
+		return nil
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		if unmapped[i].tool != unmapped[j].tool {
+			return unmapped[i].tool < unmapped[j].tool
+		}
+		return unmapped[i].rule < unmapped[j].rule
+	})
+
+	fmt.Fprintf(w, "\n%d rule(s) without a Codacy pattern, their issues were not sent. Map them in %s:\n", len(unmapped), constants.RuleMappingsFileName)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Tool\tRule\tIssues")
+	for _, rule := range unmapped {
+		fmt.Fprintf(table, "%s\t%s\t%d\n", rule.tool, rule.rule, rule.issues)
+	}
+	return table.Flush()
+}
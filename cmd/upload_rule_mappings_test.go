@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRuleMappings = `tools:
+  opengrep:
+    rules:
+      - rule: "acme.python.eval"
+        pattern: Opengrep_python.eval
+      - rule: "acme.python.*"
+        pattern: Opengrep_python.generic
+    fallback: Opengrep_generic
+  ESLint:
+    rules:
+      - rule: "custom/*"
+        pattern: ESLint9_no-eval
+`
+
+func writeRuleMappings(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "rule-mappings.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+	return filePath
+}
+
+func TestLoadRuleMappings(t *testing.T) {
+	mappings, err := loadRuleMappings(writeRuleMappings(t, testRuleMappings))
+	require.NoError(t, err)
+
+	tests := []struct {
+		toolNames []string
+		ruleID    string
+		want      string
+	}{
+		{[]string{"Opengrep", "Opengrep"}, "acme.python.eval", "Opengrep_python.eval"},
+		{[]string{"Opengrep", "Opengrep"}, "acme.python.exec", "Opengrep_python.generic"},
+		{[]string{"Opengrep", "Opengrep"}, "acme.go.sql", "Opengrep_generic"},
+		{[]string{"ESLint", "ESLint9"}, "custom/no-eval", "ESLint9_no-eval"},
+		{[]string{"ESLint", "ESLint9"}, "semi", ""},
+		{[]string{"Pylint", "pylintpython3"}, "C0114", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, mappings.patternID(tt.toolNames, tt.ruleID), tt.ruleID)
+	}
+
+	var noMappings *userRuleMappings
+	assert.Empty(t, noMappings.patternID([]string{"Opengrep"}, "acme.python.eval"))
+}
+
+func TestLoadRuleMappingsMissingFile(t *testing.T) {
+	mappings, err := loadRuleMappings(filepath.Join(t.TempDir(), "rule-mappings.yaml"))
+
+	require.NoError(t, err)
+	assert.Empty(t, mappings.Tools)
+}
+
+func TestLoadRuleMappingsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not yaml":        "tools: [",
+		"missing pattern": "tools:\n  opengrep:\n    rules:\n      - rule: acme.*\n",
+		"invalid glob":    "tools:\n  opengrep:\n    rules:\n      - rule: \"acme[\"\n        pattern: Opengrep_generic\n",
+		"duplicate tool":  "tools:\n  opengrep:\n    fallback: Opengrep_a\n  Opengrep:\n    fallback: Opengrep_b\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadRuleMappings(writeRuleMappings(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// opengrepPatterns are the Codacy patterns of Opengrep used by the tests
+var opengrepPatterns = []domain.PatternConfiguration{
+	{PatternDefinition: domain.PatternDefinition{Id: "Opengrep_python.eval", Level: "Error", Category: "Security"}},
+	{PatternDefinition: domain.PatternDefinition{Id: "Opengrep_python.generic.long", Level: "Warning", Category: "Security"}},
+	{PatternDefinition: domain.PatternDefinition{Id: "Opengrep_known", Level: "Info", Category: "CodeStyle"}},
+}
+
+// stubPatternLookup looks up the patterns of a single Codacy tool by their exact id
+func stubPatternLookup(tool domain.Tool, patterns []domain.PatternConfiguration) patternLookup {
+	return func(patternID string) (domain.Tool, *domain.PatternDefinition, error) {
+		for _, pattern := range patterns {
+			if pattern.PatternDefinition.Id == patternID {
+				return tool, &pattern.PatternDefinition, nil
+			}
+		}
+		return domain.Tool{}, nil, nil
+	}
+}
+
+// captureLog redirects the log output to a buffer until the end of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &output
+}
+
+func TestProcessSarifUsesRuleMappings(t *testing.T) {
+	mappings, err := loadRuleMappings(writeRuleMappings(t, testRuleMappings))
+	require.NoError(t, err)
+	opengrep := domain.Tool{Name: "Opengrep", Prefix: "Opengrep_"}
+	lookupToolPatterns := func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		return opengrep, opengrepPatterns, nil
+	}
+	toolResults := []domain.ToolResults{{Tool: "Opengrep", Issues: []domain.Issue{
+		{PatternID: "known", Path: "a.py", Region: domain.Region{StartLine: 1}},
+		{PatternID: "acme.python.eval", Path: "a.py", Region: domain.Region{StartLine: 2}},
+		{PatternID: "acme.python.exec", Path: "a.py", Region: domain.Region{StartLine: 3}},
+		{PatternID: "acme.go.sql", Path: "a.go", Region: domain.Region{StartLine: 4}},
+		{PatternID: "acme.go.exec", Path: "a.go", Region: domain.Region{StartLine: 5}},
+	}}}
+	logOutput := captureLog(t)
+
+	payloads, ruleMappings, err := processSarif(toolResults, nil, lookupToolPatterns, stubPatternLookup(opengrep, opengrepPatterns), mappings)
+
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	require.Len(t, ruleMappings, 1)
+	assert.Equal(t, 2, ruleMappings[0].mappedIssues())
+	assert.Equal(t, "Opengrep_python.eval", ruleMappings[0].rules["acme.python.eval"].patternID)
+	// Mapped patterns must exist with their exact id, so Opengrep_python.generic doesn't map to Opengrep_python.generic.long
+	assert.Empty(t, ruleMappings[0].rules["acme.python.exec"].patternID)
+	// The fallback pattern is not a Codacy pattern, so the rules stay unmapped
+	assert.Empty(t, ruleMappings[0].rules["acme.go.sql"].patternID)
+	assert.Equal(t, 1, strings.Count(logOutput.String(), "Warning: pattern Opengrep_generic set in rule-mappings.yaml is not a Codacy pattern"))
+	assert.Contains(t, logOutput.String(), "Warning: pattern Opengrep_python.generic set in rule-mappings.yaml is not a Codacy pattern")
+}
+
+func TestProcessSarifSendsMappedIssuesAsIssuesOfThePatternTool(t *testing.T) {
+	mappings, err := loadRuleMappings(writeRuleMappings(t, "tools:\n  acme-scanner:\n    fallback: Opengrep_python.eval\n"))
+	require.NoError(t, err)
+	lookupToolPatterns := func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		// The tool is unknown to Codacy
+		return domain.Tool{}, nil, nil
+	}
+	toolResults := []domain.ToolResults{{Tool: "acme-scanner", Files: []string{"a.py", "b.py"}, Issues: []domain.Issue{
+		{PatternID: "acme.eval", Path: "a.py", Region: domain.Region{StartLine: 2}, Message: "eval"},
+	}}}
+	lookupPattern := stubPatternLookup(domain.Tool{Name: "Opengrep", Prefix: "Opengrep_"}, opengrepPatterns)
+
+	payloads, ruleMappings, err := processSarif(toolResults, nil, lookupToolPatterns, lookupPattern, mappings)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]map[string]interface{}{newToolPayload("opengrep", []map[string]interface{}{{
+		"filename": "a.py",
+		"results": []map[string]interface{}{{"Issue": map[string]interface{}{
+			"patternId": map[string]string{"value": "Opengrep_python.eval"},
+			"filename":  "a.py",
+			"message":   map[string]string{"text": "eval"},
+			"level":     "Error",
+			"location":  map[string]interface{}{"LineLocation": map[string]int{"line": 2}},
+		}}},
+	}})}, payloads)
+	require.Len(t, ruleMappings, 1)
+	assert.Equal(t, 1, ruleMappings[0].Requests)
+	assert.Equal(t, "Opengrep_python.eval", ruleMappings[0].rules["acme.eval"].patternID)
+}
+
+func TestProcessSarifFailsWhenPatternsAreNotAvailable(t *testing.T) {
+	lookupToolPatterns := func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		return domain.Tool{}, nil, errors.New("offline")
+	}
+
+	_, _, err := processSarif([]domain.ToolResults{{Tool: "Trivy"}}, nil, lookupToolPatterns, nil, nil)
+
+	assert.ErrorContains(t, err, "failed to load the Codacy patterns of Trivy: offline")
+}
+
+func TestPrintUnmappedRules(t *testing.T) {
+	trivy := newToolRuleMappings("trivy")
+	trivy.add("CVE-1", "Trivy_vulnerability")
+	opengrep := newToolRuleMappings("opengrep")
+	opengrep.add("acme.go.sql", "")
+	opengrep.add("acme.go.sql", "")
+	opengrep.add("acme.go.exec", "")
+
+	var out bytes.Buffer
+	require.NoError(t, printUnmappedRules(&out, []*toolRuleMappings{trivy, opengrep}))
+
+	assert.Equal(t, `
+2 rule(s) without a Codacy pattern, their issues were not sent. Map them in rule-mappings.yaml:
+Tool      Rule          Issues
+opengrep  acme.go.exec  1
+opengrep  acme.go.sql   2
+`, out.String())
+
+	out.Reset()
+	require.NoError(t, printUnmappedRules(&out, []*toolRuleMappings{trivy}))
+	assert.Empty(t, out.String())
+}
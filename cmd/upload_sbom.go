@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	codacyclient "codacy/cli-v2/codacy-client"
 	"codacy/cli-v2/utils/logger"
 
 	"github.com/fatih/color"
@@ -212,7 +213,7 @@ type sbomUploadParams struct {
 func (p sbomUploadParams) uploadURL() string {
 	base := p.baseURL
 	if base == "" {
-		base = "https://app.codacy.com"
+		base = codacyclient.CodacyApiBase
 	}
 	return fmt.Sprintf("%s/api/v3/organizations/%s/%s/image-sboms", base, p.provider, p.org)
 }
@@ -1,81 +1,13 @@
 package cmd
 
 import (
-	"path/filepath"
+	"encoding/json"
 	"testing"
 	"codacy/cli-v2/domain"
 
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGetRelativePath(t *testing.T) {
-	const baseDir = "/home/user/project/src"
-
-	tests := []struct {
-		name     string
-		baseDir  string
-		fullURI  string
-		expected string
-	}{
-		{
-			name:     "1. File URI with standard path",
-			baseDir:  baseDir,
-			fullURI:  "file:///home/user/project/src/lib/file.go",
-			expected: "lib/file.go",
-		},
-		{
-			name:     "2. File URI with baseDir as the file path",
-			baseDir:  baseDir,
-			fullURI:  "file:///home/user/project/src",
-			expected: ".",
-		},
-		{
-			name:     "3. Simple path (no scheme)",
-			baseDir:  baseDir,
-			fullURI:  "/home/user/project/src/main.go",
-			expected: "main.go",
-		},
-		{
-			name:    "4. URI outside baseDir (should return absolute path if relative fails)",
-			baseDir: baseDir,
-			fullURI: "file:///etc/config/app.json",
-			// This is outside of baseDir, so we expect the absolute path starting from the baseDir root
-			expected: "../../../../etc/config/app.json",
-		},
-		{
-			name:     "5. Plain URI with different scheme (should be treated as plain path)",
-			baseDir:  baseDir,
-			fullURI:  "http://example.com/api/v1/file.go",
-			expected: "http://example.com/api/v1/file.go",
-		},
-		{
-			name:     "6. Empty URI",
-			baseDir:  baseDir,
-			fullURI:  "",
-			expected: "",
-		},
-		{
-			name:     "7. Windows path on a file URI (should correctly strip the leading slash from the path component)",
-			baseDir:  "C:\\Users\\dev\\repo",
-			fullURI:  "file:///C:/Users/dev/repo/app/main.go",
-			expected: "/C:/Users/dev/repo/app/main.go",
-		},
-		{
-			name:     "8. URI with spaces (URL encoded)",
-			baseDir:  baseDir,
-			fullURI:  "file:///home/user/project/src/file%20with%20spaces.go",
-			expected: "file with spaces.go",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			actual := getRelativePath(tt.baseDir, tt.fullURI)
-			expectedNormalized := filepath.FromSlash(tt.expected)
-			assert.Equal(t, expectedNormalized, actual, "Relative path should match expected")
-		})
-	}
-}
 func TestGetToolShortName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -153,3 +85,61 @@ func TestGetPatternByID(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func TestChunkToolPayload(t *testing.T) {
+	fileResults := func(filename string) map[string]interface{} {
+		return map[string]interface{}{
+			"filename": filename,
+			"results":  []map[string]interface{}{{"Issue": map[string]interface{}{"filename": filename, "message": map[string]string{"text": "some issue message"}}}},
+		}
+	}
+	results := []map[string]interface{}{fileResults("a.js"), fileResults("b.js"), fileResults("c.js")}
+	single, err := json.Marshal(newToolPayload("eslint-8", results[:1]))
+	assert.NoError(t, err)
+
+	payloads := chunkToolPayload("eslint-8", results, 2*len(single))
+	assert.Len(t, payloads, 2)
+	assert.Equal(t, newToolPayload("eslint-8", results[:2]), payloads[0])
+	assert.Equal(t, newToolPayload("eslint-8", results[2:]), payloads[1])
+	for _, payload := range payloads {
+		payloadBytes, err := json.Marshal(payload)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(payloadBytes), 2*len(single))
+	}
+
+	// Files whose results exceed the limit are sent alone
+	assert.Len(t, chunkToolPayload("eslint-8", results, 10), 3)
+	// Everything fits in a single payload
+	assert.Equal(t, [][]map[string]interface{}{newToolPayload("eslint-8", results)}, chunkToolPayload("eslint-8", results, maxUploadChunkBytes))
+	// Tools without results still send a payload
+	assert.Equal(t, [][]map[string]interface{}{newToolPayload("eslint-8", nil)}, chunkToolPayload("eslint-8", nil, maxUploadChunkBytes))
+}
+
+func TestProcessSarifUploadsEveryLocation(t *testing.T) {
+	lookupToolPatterns := func(toolName string) (domain.Tool, []domain.PatternConfiguration, error) {
+		return domain.Tool{Name: "Opengrep", Prefix: "Opengrep_"}, []domain.PatternConfiguration{
+			{PatternDefinition: domain.PatternDefinition{Id: "Opengrep_taint", Level: "Error", Category: "Security"}},
+		}, nil
+	}
+	toolResults := []domain.ToolResults{{Tool: "Opengrep", Issues: []domain.Issue{{
+		PatternID: "taint",
+		Path:      "src/a.py",
+		Region:    domain.Region{StartLine: 3},
+		AdditionalLocations: []domain.Location{
+			{Path: "src/b.py", Region: domain.Region{StartLine: 7}},
+		},
+	}}}}
+
+	payloads, _, err := processSarif(toolResults, nil, lookupToolPatterns, nil, nil)
+	assert.NoError(t, err)
+
+	results := payloads[0][0]["issues"].(map[string]interface{})["Success"].(map[string]interface{})["results"].([]map[string]interface{})
+	lines := map[string]int{}
+	for _, fileResults := range results {
+		for _, result := range fileResults["results"].([]map[string]interface{}) {
+			issue := result["Issue"].(map[string]interface{})
+			lines[issue["filename"].(string)] = issue["location"].(map[string]interface{})["LineLocation"].(map[string]int)["line"]
+		}
+	}
+	assert.Equal(t, map[string]int{"src/a.py": 3, "src/b.py": 7}, lines)
+}
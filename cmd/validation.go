@@ -10,6 +10,7 @@ import (
 	"codacy/cli-v2/utils/logger"
 
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
@@ -95,3 +96,27 @@ func shouldSkipValidation(cmdName string) bool {
 
 	return false
 }
+
+// commandGroupsSkippingValidation are the top-level commands whose subcommands skip codacy.yaml validation
+var commandGroupsSkippingValidation = []string{
+	"cache", // the results cache is global, not tied to codacy.yaml
+	"sarif", // SARIF files are processed offline, without codacy.yaml
+}
+
+// shouldSkipValidationForCommand checks if a command should skip codacy.yaml validation, by its name or, for the
+// subcommands of a group such as sarif merge, by the top-level command of its path
+func shouldSkipValidationForCommand(cmd *cobra.Command) bool {
+	if shouldSkipValidation(cmd.Name()) {
+		return true
+	}
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) < 2 {
+		return false
+	}
+	for _, group := range commandGroupsSkippingValidation {
+		if path[1] == group {
+			return true
+		}
+	}
+	return false
+}
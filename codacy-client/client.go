@@ -12,8 +12,11 @@ import (
 
 const timeout = 10 * time.Second
 
-// CodacyApiBase is the base URL for the Codacy API
-var CodacyApiBase = "https://app.codacy.com"
+// DefaultCodacyApiBase is the base URL of the Codacy cloud API
+const DefaultCodacyApiBase = "https://app.codacy.com"
+
+// CodacyApiBase is the base URL for the Codacy API, which can point to a self-hosted installation
+var CodacyApiBase = DefaultCodacyApiBase
 
 func getRequest(url string, apiToken string) ([]byte, error) {
 	client := &http.Client{
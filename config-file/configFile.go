@@ -3,14 +3,17 @@ package config_file
 import (
 	"codacy/cli-v2/config"
 	"codacy/cli-v2/plugins"
+	"fmt"
 	"os"
 
 	"gopkg.in/yaml.v3"
 )
 
 type configFile struct {
-	RUNTIMES []string `yaml:"runtimes"`
-	TOOLS    []string `yaml:"tools"`
+	RUNTIMES      []string           `yaml:"runtimes"`
+	TOOLS         []string           `yaml:"tools"`
+	QUALITY_GATE  config.QualityGate `yaml:"quality_gate"`
+	TOOL_TIMEOUTS map[string]string  `yaml:"tool_timeouts"`
 }
 
 func parseConfigFile(configContents []byte) error {
@@ -55,6 +58,24 @@ func parseConfigFile(configContents []byte) error {
 		return err
 	}
 
+	if err := config.ValidateFailOnLevel(configFile.QUALITY_GATE.FailOn); err != nil {
+		return err
+	}
+	if configFile.QUALITY_GATE.MaxIssues != nil && *configFile.QUALITY_GATE.MaxIssues < 0 {
+		return fmt.Errorf("invalid quality gate max_issues %d, expected a non-negative number", *configFile.QUALITY_GATE.MaxIssues)
+	}
+	config.Config.SetQualityGate(configFile.QUALITY_GATE)
+
+	toolTimeouts := make(config.ToolTimeouts, len(configFile.TOOL_TIMEOUTS))
+	for toolName, value := range configFile.TOOL_TIMEOUTS {
+		timeout, err := config.ParseToolTimeout(value)
+		if err != nil {
+			return fmt.Errorf("tool_timeouts.%s: %w", toolName, err)
+		}
+		toolTimeouts[toolName] = timeout
+	}
+	config.Config.SetToolTimeouts(toolTimeouts)
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"codacy/cli-v2/constants"
 	"codacy/cli-v2/plugins"
@@ -16,6 +17,8 @@ import (
 // CliConfigYaml defines the structure for parsing .codacy/cli-config.yaml
 type CliConfigYaml struct {
 	Mode string `yaml:"mode"`
+	// APIURL is the base URL of the Codacy API, e.g. of a self-hosted installation
+	APIURL string `yaml:"api_url,omitempty"`
 }
 
 type ConfigType struct {
@@ -29,8 +32,54 @@ type ConfigType struct {
 	projectConfigFile    string
 	cliConfigFile        string
 
-	runtimes map[string]*plugins.RuntimeInfo
-	tools    map[string]*plugins.ToolInfo
+	runtimes     map[string]*plugins.RuntimeInfo
+	tools        map[string]*plugins.ToolInfo
+	qualityGate  QualityGate
+	toolTimeouts ToolTimeouts
+	apiURL       string
+}
+
+// QualityGate defines when analyze should fail, as configured in the quality_gate section of codacy.yaml
+type QualityGate struct {
+	// FailOn is the minimum SARIF level (error, warning or note) of the issues that fail the gate
+	FailOn string `yaml:"fail_on,omitempty"`
+	// MaxIssues is the number of issues allowed before failing the gate, nil when there is no limit
+	MaxIssues *int `yaml:"max_issues,omitempty"`
+	// FailOnToolError fails the gate when a tool fails to run
+	FailOnToolError bool `yaml:"fail_on_tool_error,omitempty"`
+}
+
+// ValidateFailOnLevel checks that a fail_on value is a supported SARIF level
+func ValidateFailOnLevel(level string) error {
+	switch level {
+	case "", "error", "warning", "note":
+		return nil
+	}
+	return fmt.Errorf("invalid quality gate level %q, expected one of: error, warning, note", level)
+}
+
+// DefaultToolTimeoutKey is the tool_timeouts entry that applies to the tools without their own timeout
+const DefaultToolTimeoutKey = "default"
+
+// ToolTimeouts maps tool names to the maximum time each tool may run, as configured in the tool_timeouts
+// section of codacy.yaml
+type ToolTimeouts map[string]time.Duration
+
+// For returns the timeout of a tool, falling back to the default timeout. It returns 0 when the tool has no timeout.
+func (t ToolTimeouts) For(toolName string) time.Duration {
+	if timeout, ok := t[toolName]; ok {
+		return timeout
+	}
+	return t[DefaultToolTimeoutKey]
+}
+
+// ParseToolTimeout parses a tool timeout like "90s" or "10m"
+func ParseToolTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid tool timeout %q, expected a positive duration like 90s or 10m", value)
+	}
+	return timeout, nil
 }
 
 func (c *ConfigType) RepositoryDirectory() string {
@@ -65,6 +114,31 @@ func (c *ConfigType) CliConfigFile() string {
 	return c.cliConfigFile
 }
 
+func (c *ConfigType) QualityGate() QualityGate {
+	return c.qualityGate
+}
+
+func (c *ConfigType) SetQualityGate(qualityGate QualityGate) {
+	c.qualityGate = qualityGate
+}
+
+func (c *ConfigType) ToolTimeouts() ToolTimeouts {
+	return c.toolTimeouts
+}
+
+func (c *ConfigType) SetToolTimeouts(toolTimeouts ToolTimeouts) {
+	c.toolTimeouts = toolTimeouts
+}
+
+// APIURL is the base URL of the Codacy API to keep in cli-config.yaml, empty for the default Codacy cloud API
+func (c *ConfigType) APIURL() string {
+	return c.apiURL
+}
+
+func (c *ConfigType) SetAPIURL(apiURL string) {
+	c.apiURL = apiURL
+}
+
 func (c *ConfigType) Runtimes() map[string]*plugins.RuntimeInfo {
 	return c.runtimes
 }
@@ -461,3 +535,22 @@ func (c *ConfigType) GetCliMode() (string, error) {
 
 	return currentCliMode, nil
 }
+
+// GetCliAPIURL reads the api_url setting of the .codacy/cli-config.yaml file.
+// It returns an empty URL when the file doesn't exist or has no api_url.
+func (c *ConfigType) GetCliAPIURL() (string, error) {
+	cliConfigFilePath := c.CliConfigFile()
+	content, err := os.ReadFile(cliConfigFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", cliConfigFilePath, err)
+	}
+
+	var parsedCliConfig CliConfigYaml
+	if err := yaml.Unmarshal(content, &parsedCliConfig); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", cliConfigFilePath, err)
+	}
+	return parsedCliConfig.APIURL, nil
+}
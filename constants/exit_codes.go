@@ -0,0 +1,11 @@
+package constants
+
+// Exit codes of the analyze command, so CI pipelines can tell failures apart
+const (
+	// ExitCodeQualityGateFailed is returned when the issues found exceed the quality gate thresholds
+	ExitCodeQualityGateFailed = 3
+	// ExitCodeToolError is returned when a tool fails to run and the quality gate fails on tool errors
+	ExitCodeToolError = 4
+	// ExitCodeInterrupted is returned when the analysis is stopped by SIGINT or SIGTERM
+	ExitCodeInterrupted = 130
+)
@@ -5,6 +5,8 @@ const (
 	// Language and project configuration files
 	LanguagesConfigFileName = "languages-config.yaml"
 	GitIgnoreFileName       = ".gitignore"
+	BaselineFileName        = "baseline.json"
+	RuleMappingsFileName    = "rule-mappings.yaml"
 
 	// Tool-specific configuration files
 	ESLintConfigFileName       = "eslint.config.mjs"
@@ -0,0 +1,118 @@
+package domain
+
+// Issue is a finding reported by a tool, normalized so every tool looks the same
+type Issue struct {
+	// Tool is the name of the tool that reported the issue, as in the SARIF driver name (e.g. "ESLint")
+	Tool        string `json:"tool"`
+	ToolVersion string `json:"toolVersion,omitempty"`
+	// PatternID is the rule id reported by the tool
+	PatternID string `json:"patternId"`
+	// Path is the slash separated path of the file, relative to the analyzed directory.
+	// It is empty for issues that are not located in a file.
+	Path   string `json:"path,omitempty"`
+	Region Region `json:"region"`
+	// AdditionalLocations are the other locations of the issue, when the tool reports more than one
+	AdditionalLocations []Location `json:"additionalLocations,omitempty"`
+	// Level is the SARIF level of the issue: error, warning, note or none
+	Level string `json:"level"`
+	// Severity is the severity of the Codacy pattern of the issue, e.g. Critical or Medium, when known
+	Severity    string `json:"severity,omitempty"`
+	Category    string `json:"category,omitempty"`
+	Message     string `json:"message"`
+	Fix         *Fix   `json:"fix,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	// Suppressed is set when the issue was suppressed in the source code, e.g. with a codacy:ignore comment.
+	// Suppressed issues are kept for audit purposes but are not reported.
+	Suppressed bool `json:"suppressed,omitempty"`
+}
+
+// Location is a region of a file
+type Location struct {
+	Path   string `json:"path"`
+	Region Region `json:"region"`
+}
+
+// Locations returns the location of the issue followed by its additional locations, leaving out the ones
+// without a file
+func (i Issue) Locations() []Location {
+	var locations []Location
+	if i.Path != "" {
+		locations = append(locations, Location{Path: i.Path, Region: i.Region})
+	}
+	for _, location := range i.AdditionalLocations {
+		if location.Path != "" {
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
+// Region is the position of an issue in a file. Lines and columns start at 1, and 0 means unknown.
+type Region struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// Fix is a change proposed by the tool to solve an issue
+type Fix struct {
+	Description  string        `json:"description,omitempty"`
+	Replacements []Replacement `json:"replacements,omitempty"`
+}
+
+// Replacement replaces the content of a region of a file with new text
+type Replacement struct {
+	Path         string `json:"path"`
+	Region       Region `json:"region"`
+	InsertedText string `json:"insertedText"`
+}
+
+// ToolResults holds the issues reported by a tool run
+type ToolResults struct {
+	Tool    string `json:"tool"`
+	Version string `json:"version,omitempty"`
+	// Files are the files the tool reported as analyzed, when the tool reports them
+	Files  []string `json:"files,omitempty"`
+	Issues []Issue  `json:"issues"`
+	// Metrics are the metrics of each file, when the tool reports them
+	Metrics []FileMetrics `json:"metrics,omitempty"`
+}
+
+// FileMetrics are the size and complexity metrics of a file, as reported by Lizard
+type FileMetrics struct {
+	// Path is the slash separated path of the file, relative to the analyzed directory.
+	// It is empty in SARIF, where the metrics are in the properties of the artifact of the file.
+	Path string `json:"path,omitempty"`
+	// Nloc is the number of lines of code, without comments and blank lines
+	Nloc int `json:"nloc"`
+	// Ccn is the sum of the cyclomatic complexity of the functions of the file, and MaxCcn the highest one
+	Ccn       int `json:"ccn"`
+	MaxCcn    int `json:"maxCcn"`
+	Functions int `json:"functions"`
+	// LineComplexities are the cyclomatic complexity of each function, at the line where it starts
+	LineComplexities []LineComplexity `json:"lineComplexities,omitempty"`
+	// Duplication are the blocks of code of the file also found elsewhere, in this file or in others
+	Duplication []DuplicatedBlock `json:"duplication,omitempty"`
+}
+
+// LineComplexity is the cyclomatic complexity of the function starting at Line
+type LineComplexity struct {
+	Line  int `json:"line"`
+	Value int `json:"value"`
+}
+
+// DuplicatedBlock is a block of code found in several places, as reported by Lizard
+type DuplicatedBlock struct {
+	// Lines is the code of the block, as found in its first location
+	Lines     string               `json:"lines,omitempty"`
+	Locations []DuplicatedLocation `json:"locations"`
+}
+
+// DuplicatedLocation is a place where a duplicated block of code is found
+type DuplicatedLocation struct {
+	// Path is the slash separated path of the file, relative to the analyzed directory
+	Path      string `json:"path"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+}
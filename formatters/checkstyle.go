@@ -0,0 +1,121 @@
+package formatters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"codacy/cli-v2/domain"
+)
+
+type checkstyleFormatter struct{}
+
+func init() {
+	RegisterFormatter(checkstyleFormatter{})
+}
+
+func (checkstyleFormatter) Name() string {
+	return "checkstyle"
+}
+
+// checkstyleVersion is the version of the checkstyle format, as written by checkstyle itself
+const checkstyleVersion = "4.3"
+
+// checkstyleSeverities maps the SARIF levels, and the Codacy severities of the patterns of the issues, to
+// checkstyle severities. Patterns without a severity have their level as severity, e.g. Info.
+var checkstyleSeverities = map[string]string{
+	"error":    "error",
+	"warning":  "warning",
+	"note":     "info",
+	"none":     "info",
+	"info":     "info",
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"minor":    "info",
+	"low":      "info",
+}
+
+type checkstyleDocument struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Format writes the issues of every tool in a single checkstyle document, with one file element per analyzed file.
+// Checkstyle reports issues of files, so issues without a file are left out.
+func (checkstyleFormatter) Format(w io.Writer, report *Report) error {
+	issuesByFile := make(map[string][]domain.Issue)
+	for _, toolResults := range report.ToolResults {
+		for _, file := range toolResults.Files {
+			if _, ok := issuesByFile[file]; !ok {
+				issuesByFile[file] = nil
+			}
+		}
+	}
+	for _, issue := range sortedIssues(report) {
+		if issue.Path == "" {
+			continue
+		}
+		issuesByFile[issue.Path] = append(issuesByFile[issue.Path], issue)
+	}
+
+	files := make([]string, 0, len(issuesByFile))
+	for file := range issuesByFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	document := checkstyleDocument{Version: checkstyleVersion}
+	for _, file := range files {
+		element := checkstyleFile{Name: file}
+		for _, issue := range issuesByFile[file] {
+			element.Errors = append(element.Errors, checkstyleError{
+				Line:     issue.Region.StartLine,
+				Column:   issue.Region.StartColumn,
+				Severity: checkstyleSeverity(issue),
+				Message:  singleLine(issue.Message),
+				Source:   fmt.Sprintf("%s.%s", issue.Tool, issue.PatternID),
+			})
+		}
+		document.Files = append(document.Files, element)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to write checkstyle report: %w", err)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// checkstyleSeverity maps the Codacy severity of an issue, or its level when the severity is unknown, to a
+// checkstyle severity. Unknown levels are warnings.
+func checkstyleSeverity(issue domain.Issue) string {
+	if severity, ok := checkstyleSeverities[strings.ToLower(issue.Severity)]; ok {
+		return severity
+	}
+	if severity, ok := checkstyleSeverities[strings.ToLower(issueLevel(issue))]; ok {
+		return severity
+	}
+	return "warning"
+}
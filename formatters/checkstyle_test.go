@@ -0,0 +1,42 @@
+package formatters
+
+import (
+	"bytes"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckstyleFormatter(t *testing.T) {
+	report := sampleReport()
+	report.ToolResults[2].Files = []string{"Dockerfile", "src/a.js"}
+
+	var output bytes.Buffer
+	require.NoError(t, checkstyleFormatter{}.Format(&output, report))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="Dockerfile"></file>
+  <file name="src/a.js">
+    <error line="2" severity="warning" message="Line too long" source="Pylint.C0301"></error>
+    <error line="10" column="1" severity="warning" message="Missing semicolon." source="ESLint.semi"></error>
+  </file>
+  <file name="src/b.js">
+    <error line="3" column="7" severity="error" message="&#39;x&#39; is defined but never used." source="ESLint.no-unused-vars"></error>
+  </file>
+</checkstyle>
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestCheckstyleSeverity(t *testing.T) {
+	assert.Equal(t, "error", checkstyleSeverity(domain.Issue{Level: "error"}))
+	assert.Equal(t, "warning", checkstyleSeverity(domain.Issue{}))
+	assert.Equal(t, "info", checkstyleSeverity(domain.Issue{Level: "note"}))
+	assert.Equal(t, "error", checkstyleSeverity(domain.Issue{Level: "warning", Severity: "Critical"}))
+	assert.Equal(t, "info", checkstyleSeverity(domain.Issue{Level: "error", Severity: "Minor"}))
+	assert.Equal(t, "warning", checkstyleSeverity(domain.Issue{Level: "unexpected"}))
+}
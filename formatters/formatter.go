@@ -0,0 +1,203 @@
+// Package formatters renders the issues of an analysis in the output formats supported by analyze
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/utils"
+)
+
+// Report holds everything a formatter needs to render the results of an analysis
+type Report struct {
+	// ToolResults are the issues reported by each tool that ran
+	ToolResults []domain.ToolResults
+	// Colored enables terminal colors in the formats that support them
+	Colored bool
+	// BaseDir is the analyzed directory, from which the formats showing source code read the files
+	BaseDir string
+	// RepositoryPrefix is the slash separated path of BaseDir within its git repository, ending with a slash,
+	// so the formats linking to files can link relative to the repository root
+	RepositoryPrefix string
+	// Patterns describe the rules of the tools, when their descriptions are known
+	Patterns []PatternDescription
+}
+
+// PatternDescription is the title and description of a rule of a tool
+type PatternDescription struct {
+	// Tool is the name of the tool, matched with the SARIF driver names like in codacy:ignore comments
+	Tool        string
+	ID          string
+	Title       string
+	Description string
+}
+
+// patternDescriptions finds the description of the rule of each issue
+type patternDescriptions map[string][]PatternDescription
+
+func newPatternDescriptions(patterns []PatternDescription) patternDescriptions {
+	descriptions := make(patternDescriptions, len(patterns))
+	for _, pattern := range patterns {
+		id := strings.ToLower(pattern.ID)
+		descriptions[id] = append(descriptions[id], pattern)
+	}
+	return descriptions
+}
+
+// find returns the description of the rule of an issue, if known
+func (d patternDescriptions) find(issue domain.Issue) (PatternDescription, bool) {
+	for _, pattern := range d[strings.ToLower(issue.PatternID)] {
+		if utils.MatchesToolName(issue.Tool, pattern.Tool) {
+			return pattern, true
+		}
+	}
+	return PatternDescription{}, false
+}
+
+// Formatter renders a report in an output format
+type Formatter interface {
+	// Name returns the name of the format, as given to --format
+	Name() string
+	// Format writes the report to w
+	Format(w io.Writer, report *Report) error
+}
+
+var (
+	formattersMutex sync.RWMutex
+	formatters      = make(map[string]Formatter)
+)
+
+// RegisterFormatter makes a formatter available by its name. It is meant to be called from init functions
+// and panics if a formatter with the same name is already registered.
+func RegisterFormatter(formatter Formatter) {
+	formattersMutex.Lock()
+	defer formattersMutex.Unlock()
+
+	if _, exists := formatters[formatter.Name()]; exists {
+		panic(fmt.Sprintf("formatter %s is already registered", formatter.Name()))
+	}
+	formatters[formatter.Name()] = formatter
+}
+
+// GetFormatter returns the formatter registered for a format
+func GetFormatter(format string) (Formatter, bool) {
+	formattersMutex.RLock()
+	defer formattersMutex.RUnlock()
+
+	formatter, ok := formatters[format]
+	return formatter, ok
+}
+
+// RegisteredFormats returns the sorted names of all registered formats
+func RegisteredFormats() []string {
+	formattersMutex.RLock()
+	defer formattersMutex.RUnlock()
+
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// levels are the SARIF levels, from the most to the least severe
+var levels = []string{"error", "warning", "note", "none"}
+
+// levelRank orders levels from the most to the least severe, unknown levels being as severe as warnings
+func levelRank(level string) int {
+	for rank, known := range levels {
+		if known == level {
+			return rank
+		}
+	}
+	return 1
+}
+
+// issueLevel returns the SARIF level of an issue, which is a warning when unset, as per the SARIF spec
+func issueLevel(issue domain.Issue) string {
+	if issue.Level == "" {
+		return "warning"
+	}
+	return issue.Level
+}
+
+// reportedIssues returns the issues that are not suppressed
+func reportedIssues(issues []domain.Issue) []domain.Issue {
+	reported := make([]domain.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if !issue.Suppressed {
+			reported = append(reported, issue)
+		}
+	}
+	return reported
+}
+
+// suppressedCount counts the suppressed issues of every tool
+func suppressedCount(report *Report) int {
+	count := 0
+	for _, toolResults := range report.ToolResults {
+		count += len(toolResults.Issues) - len(reportedIssues(toolResults.Issues))
+	}
+	return count
+}
+
+// sortedIssues returns the reported issues of every tool, sorted by file, position and tool.
+// Issues without a file come first.
+func sortedIssues(report *Report) []domain.Issue {
+	var issues []domain.Issue
+	for _, toolResults := range report.ToolResults {
+		issues = append(issues, reportedIssues(toolResults.Issues)...)
+	}
+	return sortedByPosition(issues)
+}
+
+// sortedByPosition sorts issues in place by file, position and tool, and returns them
+func sortedByPosition(issues []domain.Issue) []domain.Issue {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Region.StartLine != b.Region.StartLine {
+			return a.Region.StartLine < b.Region.StartLine
+		}
+		if a.Region.StartColumn != b.Region.StartColumn {
+			return a.Region.StartColumn < b.Region.StartColumn
+		}
+		return a.Tool < b.Tool
+	})
+	return issues
+}
+
+// levelCounts counts the reported issues of a tool by level
+func levelCounts(issues []domain.Issue) map[string]int {
+	counts := make(map[string]int, len(levels))
+	for _, issue := range reportedIssues(issues) {
+		counts[issueLevel(issue)]++
+	}
+	return counts
+}
+
+// issueLocation formats the position of an issue as line:column, omitting unknown parts
+func issueLocation(issue domain.Issue) string {
+	switch {
+	case issue.Region.StartLine <= 0:
+		return "-"
+	case issue.Region.StartColumn <= 0:
+		return fmt.Sprint(issue.Region.StartLine)
+	}
+	return fmt.Sprintf("%d:%d", issue.Region.StartLine, issue.Region.StartColumn)
+}
+
+// plural returns the singular or plural form of a noun preceded by a count
+func plural(count int, singular string, pluralForm string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, pluralForm)
+}
@@ -0,0 +1,86 @@
+package formatters
+
+import (
+	"io"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeFormatter struct{}
+
+func (fakeFormatter) Name() string {
+	return "fake"
+}
+
+func (fakeFormatter) Format(w io.Writer, report *Report) error {
+	return nil
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter(fakeFormatter{})
+	defer func() {
+		formattersMutex.Lock()
+		delete(formatters, "fake")
+		formattersMutex.Unlock()
+	}()
+
+	formatter, ok := GetFormatter("fake")
+	assert.True(t, ok)
+	assert.Equal(t, fakeFormatter{}, formatter)
+	assert.Contains(t, RegisteredFormats(), "fake")
+	assert.Contains(t, RegisteredFormats(), "text")
+
+	assert.Panics(t, func() { RegisterFormatter(fakeFormatter{}) })
+
+	_, ok = GetFormatter("unknown")
+	assert.False(t, ok)
+}
+
+// sampleReport has issues of two tools in two files, plus an issue without a file and a tool without issues
+func sampleReport() *Report {
+	return &Report{
+		ToolResults: []domain.ToolResults{
+			{
+				Tool:    "ESLint",
+				Version: "8.57.0",
+				Issues: []domain.Issue{
+					{Tool: "ESLint", PatternID: "no-unused-vars", Path: "src/b.js", Region: domain.Region{StartLine: 3, StartColumn: 7}, Level: "error", Message: "'x' is defined\nbut never used."},
+					{Tool: "ESLint", PatternID: "semi", Path: "src/a.js", Region: domain.Region{StartLine: 10, StartColumn: 1}, Level: "warning", Message: "Missing semicolon."},
+				},
+			},
+			{
+				Tool: "Pylint",
+				Issues: []domain.Issue{
+					{Tool: "Pylint", PatternID: "C0301", Path: "src/a.js", Region: domain.Region{StartLine: 2}, Message: "Line too long"},
+					{Tool: "Pylint", PatternID: "R0801", Level: "note", Message: "Similar lines in 2 files"},
+				},
+			},
+			{Tool: "Trivy"},
+		},
+	}
+}
+
+func TestSortedIssues(t *testing.T) {
+	issues := sortedIssues(sampleReport())
+
+	var order []string
+	for _, issue := range issues {
+		order = append(order, issue.PatternID)
+	}
+	assert.Equal(t, []string{"R0801", "C0301", "semi", "no-unused-vars"}, order)
+}
+
+func TestIssueHelpers(t *testing.T) {
+	assert.Equal(t, "warning", issueLevel(domain.Issue{}))
+	assert.Equal(t, "note", issueLevel(domain.Issue{Level: "note"}))
+
+	assert.Equal(t, "-", issueLocation(domain.Issue{}))
+	assert.Equal(t, "4", issueLocation(domain.Issue{Region: domain.Region{StartLine: 4}}))
+	assert.Equal(t, "4:2", issueLocation(domain.Issue{Region: domain.Region{StartLine: 4, StartColumn: 2}}))
+
+	assert.Equal(t, "1 file", plural(1, "file", "files"))
+	assert.Equal(t, "0 files", plural(0, "file", "files"))
+}
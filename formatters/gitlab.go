@@ -0,0 +1,137 @@
+package formatters
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"codacy/cli-v2/domain"
+)
+
+type gitlabFormatter struct{}
+
+func init() {
+	RegisterFormatter(gitlabFormatter{})
+}
+
+func (gitlabFormatter) Name() string {
+	return "gitlab"
+}
+
+// GitLab Code Quality severities
+const (
+	gitlabInfo     = "info"
+	gitlabMinor    = "minor"
+	gitlabMajor    = "major"
+	gitlabCritical = "critical"
+	gitlabBlocker  = "blocker"
+)
+
+// gitlabSeverities maps the SARIF levels, and the Codacy severities of the patterns of the issues, to GitLab
+// severities. Patterns without a severity have their level as severity, e.g. Info.
+var gitlabSeverities = map[string]string{
+	"error":    gitlabCritical,
+	"warning":  gitlabMajor,
+	"note":     gitlabMinor,
+	"none":     gitlabInfo,
+	"info":     gitlabMinor,
+	"critical": gitlabCritical,
+	"high":     gitlabMajor,
+	"medium":   gitlabMinor,
+	"minor":    gitlabMinor,
+	"low":      gitlabInfo,
+}
+
+// gitlabIssue is an issue of a GitLab Code Quality report
+type gitlabIssue struct {
+	Type        string         `json:"type"`
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	EngineName  string         `json:"engine_name"`
+	Categories  []string       `json:"categories,omitempty"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// Format writes the issues as a GitLab Code Quality report. GitLab only shows issues located in a file,
+// so issues without a file are left out.
+func (gitlabFormatter) Format(w io.Writer, report *Report) error {
+	issues := make([]gitlabIssue, 0)
+	occurrences := make(map[string]int)
+
+	for _, issue := range sortedIssues(report) {
+		if issue.Path == "" {
+			continue
+		}
+
+		gitlab := gitlabIssue{
+			Type:        "issue",
+			Description: singleLine(issue.Message),
+			CheckName:   issue.PatternID,
+			EngineName:  issue.Tool,
+			Fingerprint: gitlabFingerprint(issue, occurrences[issue.Fingerprint]),
+			Severity:    gitlabSeverity(issue),
+			Location: gitlabLocation{
+				Path:  issue.Path,
+				Lines: gitlabLines{Begin: max(issue.Region.StartLine, 1)},
+			},
+		}
+		if issue.Category != "" {
+			gitlab.Categories = []string{issue.Category}
+		}
+		if issue.Region.EndLine > gitlab.Location.Lines.Begin {
+			gitlab.Location.Lines.End = issue.Region.EndLine
+		}
+		occurrences[issue.Fingerprint]++
+
+		issues = append(issues, gitlab)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(issues); err != nil {
+		return fmt.Errorf("failed to write GitLab Code Quality report: %w", err)
+	}
+	return nil
+}
+
+// gitlabSeverity maps the Codacy severity of an issue, or its level when the severity is unknown, to a GitLab
+// severity. Critical security issues are blockers.
+func gitlabSeverity(issue domain.Issue) string {
+	severity, ok := gitlabSeverities[strings.ToLower(issue.Severity)]
+	if !ok {
+		severity, ok = gitlabSeverities[strings.ToLower(issueLevel(issue))]
+	}
+	if !ok {
+		severity = gitlabMajor
+	}
+	if severity == gitlabCritical && strings.EqualFold(issue.Category, "security") {
+		return gitlabBlocker
+	}
+	return severity
+}
+
+// gitlabFingerprint returns a fingerprint unique to each issue of the report, which doesn't change between
+// analyses as long as the flagged code doesn't change. Issues sharing a fingerprint, e.g. the same rule
+// reported twice on a line, are told apart by their order.
+func gitlabFingerprint(issue domain.Issue, occurrence int) string {
+	if occurrence == 0 {
+		return issue.Fingerprint
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", issue.Fingerprint, occurrence)))
+	return hex.EncodeToString(hash[:])
+}
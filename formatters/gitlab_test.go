@@ -0,0 +1,122 @@
+package formatters
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabFormatter(t *testing.T) {
+	report := sampleReport()
+	for i := range report.ToolResults {
+		for j := range report.ToolResults[i].Issues {
+			report.ToolResults[i].Issues[j].Fingerprint = report.ToolResults[i].Issues[j].PatternID + "-fingerprint"
+		}
+	}
+	report.ToolResults[0].Issues[0].Region.EndLine = 5
+
+	var output bytes.Buffer
+	require.NoError(t, gitlabFormatter{}.Format(&output, report))
+
+	expected := `[
+  {
+    "type": "issue",
+    "description": "Line too long",
+    "check_name": "C0301",
+    "engine_name": "Pylint",
+    "fingerprint": "C0301-fingerprint",
+    "severity": "major",
+    "location": {
+      "path": "src/a.js",
+      "lines": {
+        "begin": 2
+      }
+    }
+  },
+  {
+    "type": "issue",
+    "description": "Missing semicolon.",
+    "check_name": "semi",
+    "engine_name": "ESLint",
+    "fingerprint": "semi-fingerprint",
+    "severity": "major",
+    "location": {
+      "path": "src/a.js",
+      "lines": {
+        "begin": 10
+      }
+    }
+  },
+  {
+    "type": "issue",
+    "description": "'x' is defined but never used.",
+    "check_name": "no-unused-vars",
+    "engine_name": "ESLint",
+    "fingerprint": "no-unused-vars-fingerprint",
+    "severity": "critical",
+    "location": {
+      "path": "src/b.js",
+      "lines": {
+        "begin": 3,
+        "end": 5
+      }
+    }
+  }
+]
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestGitLabFormatterWithoutIssues(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, gitlabFormatter{}.Format(&output, &Report{}))
+
+	assert.Equal(t, "[]\n", output.String())
+}
+
+func TestGitLabFormatterFingerprintsAreUnique(t *testing.T) {
+	issue := domain.Issue{Tool: "Trivy", PatternID: "CVE-2024-0001", Path: "go.mod", Level: "error", Category: "security", Fingerprint: "abc"}
+	report := &Report{ToolResults: []domain.ToolResults{{Tool: "Trivy", Issues: []domain.Issue{issue, issue}}}}
+
+	var first, second bytes.Buffer
+	require.NoError(t, gitlabFormatter{}.Format(&first, report))
+	require.NoError(t, gitlabFormatter{}.Format(&second, report))
+	assert.Equal(t, first.String(), second.String(), "fingerprints are stable")
+
+	var issues []gitlabIssue
+	require.NoError(t, json.Unmarshal(first.Bytes(), &issues))
+	require.Len(t, issues, 2)
+	assert.Equal(t, "abc", issues[0].Fingerprint)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+	assert.Equal(t, 1, issues[0].Location.Lines.Begin, "issues without a line are on the first line")
+	assert.Equal(t, []string{"security"}, issues[0].Categories)
+}
+
+func TestGitLabSeverity(t *testing.T) {
+	tests := map[string]struct {
+		issue    domain.Issue
+		expected string
+	}{
+		"error":             {domain.Issue{Level: "error"}, "critical"},
+		"warning":           {domain.Issue{Level: "warning"}, "major"},
+		"no level":          {domain.Issue{}, "major"},
+		"note":              {domain.Issue{Level: "note"}, "minor"},
+		"none":              {domain.Issue{Level: "none"}, "info"},
+		"codacy level":      {domain.Issue{Level: "warning", Severity: "Info"}, "minor"},
+		"codacy severity":   {domain.Issue{Level: "error", Severity: "Medium"}, "minor"},
+		"unknown severity":  {domain.Issue{Level: "note", Severity: "unexpected"}, "minor"},
+		"critical security": {domain.Issue{Level: "error", Category: "Security"}, "blocker"},
+		"critical severity": {domain.Issue{Level: "warning", Severity: "Critical", Category: "Security"}, "blocker"},
+		"unknown level":     {domain.Issue{Level: "unexpected"}, "major"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, gitlabSeverity(test.issue))
+		})
+	}
+}
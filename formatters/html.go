@@ -0,0 +1,235 @@
+package formatters
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"codacy/cli-v2/domain"
+	"codacy/cli-v2/utils"
+)
+
+type htmlFormatter struct{}
+
+func init() {
+	RegisterFormatter(htmlFormatter{})
+}
+
+func (htmlFormatter) Name() string {
+	return "html"
+}
+
+const (
+	// snippetContextLines is the number of lines shown around the lines of an issue
+	snippetContextLines = 2
+	// maxSnippetIssueLines limits the highlighted lines of an issue spanning a large region
+	maxSnippetIssueLines = 10
+	// uncategorizedLabel is the category of issues whose tool doesn't report one
+	uncategorizedLabel = "Uncategorized"
+)
+
+//go:embed html/report.html
+var htmlReportTemplate string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// htmlReport is the data rendered by the HTML template
+type htmlReport struct {
+	Total      int
+	Files      int
+	Suppressed int
+	Levels     []htmlCount
+	Columns    []string
+	Tools      []htmlToolRow
+	Totals     htmlToolRow
+	Categories []htmlCount
+	ToolNames  []string
+	Issues     []htmlIssue
+	Sources    []htmlSourceFile
+}
+
+type htmlCount struct {
+	Name  string
+	Count int
+}
+
+type htmlToolRow struct {
+	Name   string
+	Counts []int
+	Total  int
+}
+
+type htmlIssue struct {
+	Anchor      string
+	Path        string
+	Line        int
+	Location    string
+	Level       string
+	LevelRank   int
+	Tool        string
+	Rule        string
+	Title       string
+	Description string
+	Category    string
+	Message     string
+}
+
+type htmlSourceFile struct {
+	Path     string
+	Snippets []htmlSnippet
+}
+
+type htmlSnippet struct {
+	Issue htmlIssue
+	Lines []htmlLine
+}
+
+type htmlLine struct {
+	Number      int
+	Text        string
+	Highlighted bool
+}
+
+// Format writes a self-contained HTML page, with its styles and scripts embedded, showing a summary of the issues,
+// a sortable and filterable table of issues and the source code flagged by each issue
+func (htmlFormatter) Format(w io.Writer, report *Report) error {
+	data := newHTMLReport(report)
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return nil
+}
+
+func newHTMLReport(report *Report) htmlReport {
+	issues := sortedIssues(report)
+	data := htmlReport{
+		Total:      len(issues),
+		Suppressed: suppressedCount(report),
+	}
+
+	counts := make(map[string]int)
+	toolCounts := make([]map[string]int, len(report.ToolResults))
+	for i, toolResults := range report.ToolResults {
+		toolCounts[i] = levelCounts(toolResults.Issues)
+		for level, count := range toolCounts[i] {
+			counts[level] += count
+		}
+		data.ToolNames = append(data.ToolNames, toolResults.Tool)
+	}
+
+	data.Columns = []string{"error", "warning", "note"}
+	if counts["none"] > 0 {
+		data.Columns = append(data.Columns, "none")
+	}
+	for _, level := range data.Columns {
+		data.Levels = append(data.Levels, htmlCount{Name: level, Count: counts[level]})
+	}
+	newRow := func(name string, counts map[string]int) htmlToolRow {
+		row := htmlToolRow{Name: name}
+		for _, level := range data.Columns {
+			row.Counts = append(row.Counts, counts[level])
+			row.Total += counts[level]
+		}
+		return row
+	}
+	for i, toolResults := range report.ToolResults {
+		data.Tools = append(data.Tools, newRow(toolResults.Tool, toolCounts[i]))
+	}
+	data.Totals = newRow("Total", counts)
+
+	categoryCounts := make(map[string]int)
+	for _, issue := range issues {
+		category := issue.Category
+		if category == "" {
+			category = uncategorizedLabel
+		}
+		categoryCounts[category]++
+	}
+	for category, count := range categoryCounts {
+		data.Categories = append(data.Categories, htmlCount{Name: category, Count: count})
+	}
+	sort.Slice(data.Categories, func(i, j int) bool {
+		a, b := data.Categories[i], data.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+
+	patterns := newPatternDescriptions(report.Patterns)
+	sources := utils.NewSourceFiles(report.BaseDir)
+	sourcesByPath := make(map[string]int)
+	for i, issue := range issues {
+		row := newHTMLIssue(i, issue, patterns)
+		data.Issues = append(data.Issues, row)
+
+		if issue.Path == "" {
+			continue
+		}
+		index, ok := sourcesByPath[issue.Path]
+		if !ok {
+			index = len(data.Sources)
+			sourcesByPath[issue.Path] = index
+			data.Sources = append(data.Sources, htmlSourceFile{Path: issue.Path})
+		}
+		if report.BaseDir != "" {
+			data.Sources[index].Snippets = append(data.Sources[index].Snippets, htmlSnippet{
+				Issue: row,
+				Lines: snippetLines(sources.Lines(issue.Path), issue.Region),
+			})
+		}
+	}
+	data.Files = len(data.Sources)
+	if report.BaseDir == "" {
+		data.Sources = nil
+	}
+
+	return data
+}
+
+func newHTMLIssue(index int, issue domain.Issue, patterns patternDescriptions) htmlIssue {
+	row := htmlIssue{
+		Anchor:    fmt.Sprintf("issue-%d", index+1),
+		Path:      issue.Path,
+		Line:      issue.Region.StartLine,
+		Location:  issueLocation(issue),
+		Level:     issueLevel(issue),
+		LevelRank: levelRank(issueLevel(issue)),
+		Tool:      issue.Tool,
+		Rule:      issue.PatternID,
+		Category:  issue.Category,
+		Message:   issue.Message,
+	}
+	if row.Path == "" {
+		row.Path = noFileLabel
+	}
+	if pattern, ok := patterns.find(issue); ok {
+		row.Title = pattern.Title
+		row.Description = pattern.Description
+	}
+	return row
+}
+
+// snippetLines returns the lines of a region with some context around them, highlighting the lines of the region.
+// It returns no lines when the region is unknown or outside the file.
+func snippetLines(lines []string, region domain.Region) []htmlLine {
+	if region.StartLine <= 0 || region.StartLine > len(lines) {
+		return nil
+	}
+	endLine := max(region.EndLine, region.StartLine)
+	endLine = min(endLine, region.StartLine+maxSnippetIssueLines-1, len(lines))
+
+	first := max(region.StartLine-snippetContextLines, 1)
+	last := min(endLine+snippetContextLines, len(lines))
+	snippet := make([]htmlLine, 0, last-first+1)
+	for number := first; number <= last; number++ {
+		snippet = append(snippet, htmlLine{
+			Number:      number,
+			Text:        lines[number-1],
+			Highlighted: number >= region.StartLine && number <= endLine,
+		})
+	}
+	return snippet
+}
@@ -0,0 +1,72 @@
+package formatters
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "src"), 0755))
+	var source []string
+	for i := 1; i <= 12; i++ {
+		source = append(source, "line "+strings.Repeat("x", i))
+	}
+	source[9] = "const y = <b>1</b>"
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "src", "a.js"), []byte(strings.Join(source, "\n")), 0644))
+
+	report := sampleReport()
+	report.BaseDir = baseDir
+	report.ToolResults[0].Issues[1].Category = "CodeStyle"
+	report.Patterns = []PatternDescription{{Tool: "eslint", ID: "semi", Title: "Require semicolons", Description: "Semicolons must be used"}}
+
+	var output bytes.Buffer
+	require.NoError(t, htmlFormatter{}.Format(&output, report))
+	html := output.String()
+
+	assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.NotRegexp(t, regexp.MustCompile(`(src|href)="(https?:)?//`), html, "the report doesn't load network assets")
+	assert.Contains(t, html, "4 issues in 2 files")
+	assert.Contains(t, html, "<td>CodeStyle</td><td class=\"number\">1</td>")
+	assert.Contains(t, html, "<td>Uncategorized</td><td class=\"number\">3</td>")
+	assert.Contains(t, html, "<tr><td>ESLint</td><td class=\"number\">1</td><td class=\"number\">1</td><td class=\"number\">0</td><td class=\"number\">2</td></tr>")
+	assert.Contains(t, html, `<span class="rule-title">Require semicolons</span>`)
+	assert.Contains(t, html, `<div class="description">Semicolons must be used</div>`)
+	assert.Contains(t, html, `<span class="highlighted"><span class="line-number">10</span>const y = &lt;b&gt;1&lt;/b&gt;</span>`)
+	assert.Contains(t, html, `<span><span class="line-number">12</span>line xxxxxxxxxxxx</span>`)
+	assert.Contains(t, html, `<h3>src/b.js</h3>`, "files that can't be read are still listed")
+	assert.Contains(t, html, `<a href="#issue-3">10:1</a>`)
+	assert.Contains(t, html, `id="issue-3"`)
+}
+
+func TestHTMLFormatterWithoutIssues(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, htmlFormatter{}.Format(&output, &Report{}))
+
+	assert.Contains(t, output.String(), "No issues found.")
+	assert.NotContains(t, output.String(), `id="issues"`)
+}
+
+func TestSnippetLines(t *testing.T) {
+	lines := []string{"1", "2", "3", "4", "5", "6"}
+
+	snippet := snippetLines(lines, domain.Region{StartLine: 2, EndLine: 3})
+	require.Len(t, snippet, 5)
+	assert.Equal(t, htmlLine{Number: 1, Text: "1"}, snippet[0])
+	assert.Equal(t, htmlLine{Number: 2, Text: "2", Highlighted: true}, snippet[1])
+	assert.Equal(t, htmlLine{Number: 3, Text: "3", Highlighted: true}, snippet[2])
+	assert.Equal(t, htmlLine{Number: 5, Text: "5"}, snippet[4])
+
+	assert.Len(t, snippetLines(lines, domain.Region{StartLine: 6}), 3)
+	assert.Nil(t, snippetLines(lines, domain.Region{}))
+	assert.Nil(t, snippetLines(lines, domain.Region{StartLine: 7}))
+}
@@ -0,0 +1,110 @@
+package formatters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"codacy/cli-v2/domain"
+)
+
+type junitFormatter struct{}
+
+func init() {
+	RegisterFormatter(junitFormatter{})
+}
+
+func (junitFormatter) Name() string {
+	return "junit"
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	File      string         `xml:"file,attr,omitempty"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Format writes one test suite per tool, with one test case per analyzed file and one failure per issue.
+// Files the tool analyzed without finding issues are passing test cases.
+func (junitFormatter) Format(w io.Writer, report *Report) error {
+	document := junitTestSuites{Name: "Codacy CLI"}
+
+	for _, toolResults := range report.ToolResults {
+		suite := junitTestSuite{Name: toolResults.Tool}
+
+		issuesByFile := make(map[string][]domain.Issue)
+		for _, issue := range reportedIssues(toolResults.Issues) {
+			issuesByFile[issue.Path] = append(issuesByFile[issue.Path], issue)
+		}
+		for _, file := range toolResults.Files {
+			if _, ok := issuesByFile[file]; !ok {
+				issuesByFile[file] = nil
+			}
+		}
+
+		files := make([]string, 0, len(issuesByFile))
+		for file := range issuesByFile {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+			testCase := junitTestCase{ClassName: toolResults.Tool, Name: file, File: file}
+			if file == "" {
+				testCase.Name = noFileLabel
+			}
+			for _, issue := range sortedByPosition(issuesByFile[file]) {
+				testCase.Failures = append(testCase.Failures, junitFailure{
+					Message: fmt.Sprintf("%s: %s", issue.PatternID, singleLine(issue.Message)),
+					Type:    issueLevel(issue),
+					Text:    fmt.Sprintf("%s:%s [%s] %s: %s", testCase.Name, issueLocation(issue), issueLevel(issue), issue.PatternID, issue.Message),
+				})
+			}
+			if len(testCase.Failures) > 0 {
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suite.Tests = len(suite.TestCases)
+
+		document.Tests += suite.Tests
+		document.Failures += suite.Failures
+		document.Suites = append(document.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
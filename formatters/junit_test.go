@@ -0,0 +1,66 @@
+package formatters
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJUnitFormatter(t *testing.T) {
+	report := sampleReport()
+	report.ToolResults[0].Files = []string{"src/a.js", "src/b.js", "src/c.js"}
+	report.ToolResults[2].Files = []string{"Dockerfile"}
+
+	var output bytes.Buffer
+	require.NoError(t, junitFormatter{}.Format(&output, report))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="Codacy CLI" tests="6" failures="4">
+  <testsuite name="ESLint" tests="3" failures="2" errors="0" skipped="0">
+    <testcase classname="ESLint" name="src/a.js" file="src/a.js">
+      <failure message="semi: Missing semicolon." type="warning">src/a.js:10:1 [warning] semi: Missing semicolon.</failure>
+    </testcase>
+    <testcase classname="ESLint" name="src/b.js" file="src/b.js">
+      <failure message="no-unused-vars: &#39;x&#39; is defined but never used." type="error">src/b.js:3:7 [error] no-unused-vars: &#39;x&#39; is defined&#xA;but never used.</failure>
+    </testcase>
+    <testcase classname="ESLint" name="src/c.js" file="src/c.js"></testcase>
+  </testsuite>
+  <testsuite name="Pylint" tests="2" failures="2" errors="0" skipped="0">
+    <testcase classname="Pylint" name="(no file)">
+      <failure message="R0801: Similar lines in 2 files" type="note">(no file):- [note] R0801: Similar lines in 2 files</failure>
+    </testcase>
+    <testcase classname="Pylint" name="src/a.js" file="src/a.js">
+      <failure message="C0301: Line too long" type="warning">src/a.js:2 [warning] C0301: Line too long</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="Trivy" tests="1" failures="0" errors="0" skipped="0">
+    <testcase classname="Trivy" name="Dockerfile" file="Dockerfile"></testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, output.String())
+
+	var parsed junitTestSuites
+	assert.NoError(t, xml.Unmarshal(output.Bytes(), &parsed))
+}
+
+func TestJUnitFormatterSkipsSuppressedIssues(t *testing.T) {
+	report := sampleReport()
+	report.ToolResults[0].Files = []string{"src/a.js", "src/b.js"}
+	report.ToolResults[0].Issues[0].Suppressed = true
+
+	var output bytes.Buffer
+	require.NoError(t, junitFormatter{}.Format(&output, report))
+
+	var parsed junitTestSuites
+	require.NoError(t, xml.Unmarshal(output.Bytes(), &parsed))
+	eslint := parsed.Suites[0]
+	assert.Equal(t, 2, eslint.Tests)
+	assert.Equal(t, 1, eslint.Failures)
+	assert.Equal(t, "src/b.js", eslint.TestCases[1].Name)
+	assert.Empty(t, eslint.TestCases[1].Failures)
+	assert.NotContains(t, output.String(), "no-unused-vars")
+}
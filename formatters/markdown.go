@@ -0,0 +1,193 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"codacy/cli-v2/domain"
+)
+
+type markdownFormatter struct{}
+
+func init() {
+	RegisterFormatter(markdownFormatter{})
+}
+
+func (markdownFormatter) Name() string {
+	return "markdown"
+}
+
+const (
+	// markdownTopIssues is the number of most severe issues listed after the summary
+	markdownTopIssues = 10
+	// markdownMaxLength keeps the report below the size limit of pull request comments (65536 characters on GitHub)
+	markdownMaxLength = 60000
+	// markdownMaxMessageLength truncates long messages, so a single issue can't take the whole report
+	markdownMaxMessageLength = 300
+)
+
+// markdownEscaper escapes the characters with a meaning in markdown or HTML, so messages are shown as written
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// Format writes a summary table of the issues of each tool by level, the most severe issues and a collapsible
+// section listing the issues of each tool. Sections are cut to keep the report below markdownMaxLength,
+// ending with the number of issues left out.
+func (markdownFormatter) Format(w io.Writer, report *Report) error {
+	var output strings.Builder
+	output.WriteString("## Codacy analysis\n\n")
+	writeMarkdownSummary(&output, report)
+
+	issues := sortedIssues(report)
+	if len(issues) == 0 {
+		_, err := io.WriteString(w, output.String())
+		return err
+	}
+
+	top := append([]domain.Issue(nil), issues...)
+	sort.SliceStable(top, func(i, j int) bool {
+		return levelRank(issueLevel(top[i])) < levelRank(issueLevel(top[j]))
+	})
+	if len(top) > markdownTopIssues {
+		top = top[:markdownTopIssues]
+	}
+	fmt.Fprintf(&output, "### Top issues\n\n| Level | Location | Tool | Rule | Message |\n| --- | --- | --- | --- | --- |\n")
+	for _, issue := range top {
+		fmt.Fprintf(&output, "| %s | %s | %s | %s | %s |\n",
+			issueLevel(issue), markdownLocation(report, issue), markdownEscaper.Replace(issue.Tool),
+			markdownCode(issue.PatternID), markdownMessage(issue.Message))
+	}
+	output.WriteString("\n")
+
+	omitted := 0
+	for _, toolResults := range report.ToolResults {
+		toolIssues := sortedByPosition(reportedIssues(toolResults.Issues))
+		if len(toolIssues) == 0 {
+			continue
+		}
+		// Once the report is full, the remaining tools are left out
+		if omitted > 0 {
+			omitted += len(toolIssues)
+			continue
+		}
+
+		var section strings.Builder
+		fmt.Fprintf(&section, "<details>\n<summary>%s: %s</summary>\n\n",
+			markdownEscaper.Replace(toolResults.Tool), plural(len(toolIssues), "issue", "issues"))
+		const sectionEnd = "\n</details>\n\n"
+
+		// Leave room for the end of the section and the footer
+		budget := markdownMaxLength - output.Len() - len(sectionEnd) - 100
+		listed := 0
+		for _, issue := range toolIssues {
+			line := fmt.Sprintf("- %s **%s** %s: %s\n", markdownLocation(report, issue), issueLevel(issue),
+				markdownCode(issue.PatternID), markdownMessage(issue.Message))
+			if section.Len()+len(line) > budget {
+				break
+			}
+			section.WriteString(line)
+			listed++
+		}
+
+		omitted += len(toolIssues) - listed
+		if listed == 0 {
+			continue
+		}
+		section.WriteString(sectionEnd)
+		output.WriteString(section.String())
+	}
+
+	if omitted > 0 {
+		fmt.Fprintf(&output, "_%s not shown. Run the analysis locally to see all the issues._\n", plural(omitted, "more issue", "more issues"))
+	}
+
+	_, err := io.WriteString(w, output.String())
+	return err
+}
+
+// writeMarkdownSummary writes the table of the issues of each tool by level, and the total number of issues
+func writeMarkdownSummary(output *strings.Builder, report *Report) {
+	columns := []string{"error", "warning", "note"}
+	totals := make(map[string]int)
+	toolCounts := make([]map[string]int, len(report.ToolResults))
+	for i, toolResults := range report.ToolResults {
+		toolCounts[i] = levelCounts(toolResults.Issues)
+		for level, count := range toolCounts[i] {
+			totals[level] += count
+		}
+	}
+	if totals["none"] > 0 {
+		columns = append(columns, "none")
+	}
+
+	output.WriteString("| Tool | " + strings.Join(titles(columns), " | ") + " | Total |\n")
+	output.WriteString("| --- |" + strings.Repeat(" ---: |", len(columns)+1) + "\n")
+	writeRow := func(name string, counts map[string]int, format string) {
+		cells := []string{fmt.Sprintf(format, name)}
+		total := 0
+		for _, level := range columns {
+			cells = append(cells, fmt.Sprintf(format, fmt.Sprint(counts[level])))
+			total += counts[level]
+		}
+		cells = append(cells, fmt.Sprintf(format, fmt.Sprint(total)))
+		output.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	for i, toolResults := range report.ToolResults {
+		writeRow(markdownEscaper.Replace(toolResults.Tool), toolCounts[i], "%s")
+	}
+	writeRow("Total", totals, "**%s**")
+	output.WriteString("\n")
+
+	issues := sortedIssues(report)
+	suppressed := ""
+	if count := suppressedCount(report); count > 0 {
+		suppressed = fmt.Sprintf(" (%s suppressed)", plural(count, "issue", "issues"))
+	}
+	if len(issues) == 0 {
+		fmt.Fprintf(output, "✔ No issues found%s\n", suppressed)
+		return
+	}
+	files := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Path != "" {
+			files[issue.Path] = true
+		}
+	}
+	fmt.Fprintf(output, "✖ **%s** in %s%s\n\n", plural(len(issues), "issue", "issues"), plural(len(files), "file", "files"), suppressed)
+}
+
+// markdownLocation links to the line of an issue, relative to the repository root
+func markdownLocation(report *Report, issue domain.Issue) string {
+	if issue.Path == "" {
+		return noFileLabel
+	}
+	target := (&url.URL{Path: report.RepositoryPrefix + issue.Path}).EscapedPath()
+	text := issue.Path
+	if issue.Region.StartLine > 0 {
+		target += fmt.Sprintf("#L%d", issue.Region.StartLine)
+		text += fmt.Sprintf(":%d", issue.Region.StartLine)
+	}
+	return fmt.Sprintf("[%s](%s)", markdownEscaper.Replace(text), target)
+}
+
+// markdownMessage formats a message on a single line, truncating it when too long
+func markdownMessage(message string) string {
+	message = singleLine(message)
+	if runes := []rune(message); len(runes) > markdownMaxMessageLength {
+		message = string(runes[:markdownMaxMessageLength-1]) + "…"
+	}
+	return markdownEscaper.Replace(message)
+}
+
+// markdownCode formats a rule id as inline code
+func markdownCode(text string) string {
+	if text == "" || strings.Contains(text, "`") {
+		return markdownEscaper.Replace(text)
+	}
+	return "`" + text + "`"
+}
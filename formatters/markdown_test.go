@@ -0,0 +1,89 @@
+package formatters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownFormatter(t *testing.T) {
+	report := sampleReport()
+	report.RepositoryPrefix = "web/"
+
+	var output bytes.Buffer
+	require.NoError(t, markdownFormatter{}.Format(&output, report))
+
+	expected := "## Codacy analysis\n\n" +
+		"| Tool | Error | Warning | Note | Total |\n" +
+		"| --- | ---: | ---: | ---: | ---: |\n" +
+		"| ESLint | 1 | 1 | 0 | 2 |\n" +
+		"| Pylint | 0 | 1 | 1 | 2 |\n" +
+		"| Trivy | 0 | 0 | 0 | 0 |\n" +
+		"| **Total** | **1** | **2** | **1** | **4** |\n\n" +
+		"✖ **4 issues** in 2 files\n\n" +
+		"### Top issues\n\n" +
+		"| Level | Location | Tool | Rule | Message |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| error | [src/b.js:3](web/src/b.js#L3) | ESLint | `no-unused-vars` | 'x' is defined but never used. |\n" +
+		"| warning | [src/a.js:2](web/src/a.js#L2) | Pylint | `C0301` | Line too long |\n" +
+		"| warning | [src/a.js:10](web/src/a.js#L10) | ESLint | `semi` | Missing semicolon. |\n" +
+		"| note | (no file) | Pylint | `R0801` | Similar lines in 2 files |\n\n" +
+		"<details>\n<summary>ESLint: 2 issues</summary>\n\n" +
+		"- [src/a.js:10](web/src/a.js#L10) **warning** `semi`: Missing semicolon.\n" +
+		"- [src/b.js:3](web/src/b.js#L3) **error** `no-unused-vars`: 'x' is defined but never used.\n" +
+		"\n</details>\n\n" +
+		"<details>\n<summary>Pylint: 2 issues</summary>\n\n" +
+		"- (no file) **note** `R0801`: Similar lines in 2 files\n" +
+		"- [src/a.js:2](web/src/a.js#L2) **warning** `C0301`: Line too long\n" +
+		"\n</details>\n\n"
+	assert.Equal(t, expected, output.String())
+}
+
+func TestMarkdownFormatterWithoutIssues(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, markdownFormatter{}.Format(&output, &Report{}))
+
+	assert.Contains(t, output.String(), "✔ No issues found")
+	assert.NotContains(t, output.String(), "Top issues")
+}
+
+func TestMarkdownFormatterIsCapped(t *testing.T) {
+	var issues []domain.Issue
+	for i := 0; i < 2000; i++ {
+		issues = append(issues, domain.Issue{
+			Tool: "PMD", PatternID: "LongRule", Path: fmt.Sprintf("src/File%04d.java", i), Region: domain.Region{StartLine: i + 1},
+			Level: "warning", Message: strings.Repeat("long message ", 10),
+		})
+	}
+	report := &Report{ToolResults: []domain.ToolResults{
+		{Tool: "PMD", Issues: issues},
+		{Tool: "Trivy", Issues: []domain.Issue{{Tool: "Trivy", PatternID: "CVE", Path: "go.mod", Level: "error", Message: "vulnerable"}}},
+	}}
+
+	var output bytes.Buffer
+	require.NoError(t, markdownFormatter{}.Format(&output, report))
+
+	assert.LessOrEqual(t, output.Len(), markdownMaxLength)
+	listed := strings.Count(output.String(), "\n- ")
+	assert.Greater(t, listed, 0)
+	assert.Contains(t, output.String(), fmt.Sprintf("_%d more issues not shown.", 2001-listed))
+	assert.Contains(t, output.String(), "| error | [go.mod](go.mod) | Trivy |", "the most severe issues come first")
+	assert.Equal(t, 1, strings.Count(output.String(), "</details>"))
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	assert.Equal(t, `a \| b \<br\> \*x\*`, markdownMessage("a | b <br>\n*x*"))
+	assert.Equal(t, "`no-var`", markdownCode("no-var"))
+	assert.Equal(t, "a\\`b", markdownCode("a`b"))
+	assert.Equal(t, "[my file.js:3](my%20file.js#L3)", markdownLocation(&Report{}, domain.Issue{Path: "my file.js", Region: domain.Region{StartLine: 3}}))
+
+	long := markdownMessage(strings.Repeat("a", markdownMaxMessageLength+10))
+	assert.Equal(t, markdownMaxMessageLength, len([]rune(long)))
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
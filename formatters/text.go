@@ -0,0 +1,216 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// noFileLabel groups the issues that are not located in a file
+const noFileLabel = "(no file)"
+
+type textFormatter struct{}
+
+func init() {
+	RegisterFormatter(textFormatter{})
+}
+
+func (textFormatter) Name() string {
+	return "text"
+}
+
+// textStyles holds the colors of the text format, which are disabled when the output isn't a terminal
+type textStyles struct {
+	file    *color.Color
+	dim     *color.Color
+	heading *color.Color
+	levels  map[string]*color.Color
+}
+
+func newTextStyles(colored bool) textStyles {
+	styles := textStyles{
+		file:    color.New(color.Bold, color.Underline),
+		dim:     color.New(color.Faint),
+		heading: color.New(color.Bold),
+		levels: map[string]*color.Color{
+			"error":   color.New(color.FgRed, color.Bold),
+			"warning": color.New(color.FgYellow),
+			"note":    color.New(color.FgCyan),
+			"none":    color.New(color.Faint),
+		},
+	}
+	for _, c := range append([]*color.Color{styles.file, styles.dim, styles.heading}, mapValues(styles.levels)...) {
+		if colored {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+	}
+	return styles
+}
+
+// Format writes the issues grouped by file, followed by a summary of the issues of each tool by level
+func (textFormatter) Format(w io.Writer, report *Report) error {
+	styles := newTextStyles(report.Colored)
+	issues := sortedIssues(report)
+
+	var locationWidth, levelWidth, toolWidth, ruleWidth int
+	for _, issue := range issues {
+		locationWidth = max(locationWidth, textWidth(issueLocation(issue)))
+		levelWidth = max(levelWidth, textWidth(issueLevel(issue)))
+		toolWidth = max(toolWidth, textWidth(issue.Tool))
+		ruleWidth = max(ruleWidth, textWidth(issue.PatternID))
+	}
+
+	files := 0
+	for i, issue := range issues {
+		if i == 0 || issue.Path != issues[i-1].Path {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			path := issue.Path
+			if path == "" {
+				path = noFileLabel
+			} else {
+				files++
+			}
+			fmt.Fprintln(w, styles.file.Sprint(path))
+		}
+
+		level := issueLevel(issue)
+		fmt.Fprintf(w, "  %s  %s  %s  %s  %s\n",
+			styles.dim.Sprint(padRight(issueLocation(issue), locationWidth)),
+			levelStyle(styles, level).Sprint(padRight(level, levelWidth)),
+			padRight(issue.Tool, toolWidth),
+			styles.dim.Sprint(padRight(issue.PatternID, ruleWidth)),
+			singleLine(issue.Message))
+	}
+	if len(issues) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	writeTextSummary(w, report, styles)
+
+	suppressedNote := ""
+	if suppressed := suppressedCount(report); suppressed > 0 {
+		suppressedNote = styles.dim.Sprintf(" (%s suppressed)", plural(suppressed, "issue", "issues"))
+	}
+
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(w, styles.levels["note"].Sprint("✔ No issues found")+suppressedNote)
+		return err
+	}
+
+	counts := levelCounts(issues)
+	_, err := fmt.Fprintf(w, "%s (%s, %s, %s) in %s%s\n",
+		levelStyle(styles, "error").Sprint("✖ "+plural(len(issues), "issue", "issues")),
+		plural(counts["error"], "error", "errors"),
+		plural(counts["warning"], "warning", "warnings"),
+		plural(counts["note"]+counts["none"], "note", "notes"),
+		plural(files, "file", "files"),
+		suppressedNote)
+	return err
+}
+
+// writeTextSummary writes a table with the number of issues of each tool by level
+func writeTextSummary(w io.Writer, report *Report, styles textStyles) {
+	columns := []string{"error", "warning", "note"}
+	totals := make(map[string]int)
+	toolCounts := make([]map[string]int, len(report.ToolResults))
+	for i, toolResults := range report.ToolResults {
+		toolCounts[i] = levelCounts(toolResults.Issues)
+		for level, count := range toolCounts[i] {
+			totals[level] += count
+		}
+	}
+	if totals["none"] > 0 {
+		columns = append(columns, "none")
+	}
+
+	rows := [][]string{append(append([]string{"Tool"}, titles(columns)...), "Total")}
+	addRow := func(name string, counts map[string]int) {
+		row := []string{name}
+		total := 0
+		for _, level := range columns {
+			row = append(row, fmt.Sprint(counts[level]))
+			total += counts[level]
+		}
+		rows = append(rows, append(row, fmt.Sprint(total)))
+	}
+	for i, toolResults := range report.ToolResults {
+		addRow(toolResults.Tool, toolCounts[i])
+	}
+	addRow("Total", totals)
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], textWidth(cell))
+		}
+	}
+
+	fmt.Fprintln(w, styles.heading.Sprint("Summary"))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i == 0 {
+				cells[i] = padRight(cell, widths[i])
+			} else {
+				cells[i] = padLeft(cell, widths[i])
+			}
+			switch {
+			case r == 0 || r == len(rows)-1:
+				cells[i] = styles.heading.Sprint(cells[i])
+			case i > 0 && i <= len(columns) && cell != "0":
+				cells[i] = levelStyle(styles, columns[i-1]).Sprint(cells[i])
+			}
+		}
+		fmt.Fprintf(w, "  %s\n", strings.Join(cells, "  "))
+	}
+	fmt.Fprintln(w)
+}
+
+// levelStyle returns the color of a level, falling back to the color of warnings for unknown levels
+func levelStyle(styles textStyles, level string) *color.Color {
+	if style, ok := styles.levels[level]; ok {
+		return style
+	}
+	return styles.levels["warning"]
+}
+
+// titles capitalizes the given levels for use as column headers
+func titles(levels []string) []string {
+	result := make([]string, len(levels))
+	for i, level := range levels {
+		result[i] = strings.ToUpper(level[:1]) + level[1:]
+	}
+	return result
+}
+
+// singleLine joins the lines of a message, so each issue takes one line
+func singleLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
+
+func textWidth(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+func padRight(text string, width int) string {
+	return text + strings.Repeat(" ", max(0, width-textWidth(text)))
+}
+
+func padLeft(text string, width int) string {
+	return strings.Repeat(" ", max(0, width-textWidth(text))) + text
+}
+
+func mapValues(m map[string]*color.Color) []*color.Color {
+	values := make([]*color.Color, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+package formatters
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextFormatter(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, textFormatter{}.Format(&output, sampleReport()))
+
+	expected := `(no file)
+  -     note     Pylint  R0801           Similar lines in 2 files
+
+src/a.js
+  2     warning  Pylint  C0301           Line too long
+  10:1  warning  ESLint  semi            Missing semicolon.
+
+src/b.js
+  3:7   error    ESLint  no-unused-vars  'x' is defined but never used.
+
+Summary
+  Tool    Error  Warning  Note  Total
+  ESLint      1        1     0      2
+  Pylint      0        1     1      2
+  Trivy       0        0     0      0
+  Total       1        2     1      4
+
+✖ 4 issues (1 error, 2 warnings, 1 note) in 2 files
+`
+	assert.Equal(t, expected, output.String())
+}
+
+func TestTextFormatterWithoutIssues(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, textFormatter{}.Format(&output, &Report{}))
+
+	assert.Contains(t, output.String(), "Summary")
+	assert.Contains(t, output.String(), "✔ No issues found")
+}
+
+func TestTextFormatterColors(t *testing.T) {
+	report := sampleReport()
+
+	var plain, colored bytes.Buffer
+	require.NoError(t, textFormatter{}.Format(&plain, report))
+	report.Colored = true
+	require.NoError(t, textFormatter{}.Format(&colored, report))
+
+	assert.NotContains(t, plain.String(), "\x1b[")
+	assert.Contains(t, colored.String(), "\x1b[")
+}
+
+func TestTextFormatterSkipsSuppressedIssues(t *testing.T) {
+	report := sampleReport()
+	report.ToolResults[0].Issues[0].Suppressed = true
+
+	var output bytes.Buffer
+	require.NoError(t, textFormatter{}.Format(&output, report))
+
+	assert.NotContains(t, output.String(), "no-unused-vars")
+	assert.Contains(t, output.String(), "✖ 3 issues (0 errors, 2 warnings, 1 note) in 1 file (1 issue suppressed)")
+}
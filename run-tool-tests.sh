@@ -38,6 +38,7 @@ sort_sarif() {
 # Check if tool name is provided
 if [ -z "$1" ]; then
   echo "Usage: $0 <tool_name>"
+  echo "Set UPDATE_EXPECTED=1 to replace the expected SARIF of the tool with the actual output"
   exit 1
 fi
 
@@ -96,6 +97,14 @@ trap cleanup_test_files EXIT
 
 # Process SARIF files
 normalize_paths actual.sarif
+
+# Update the expected output, e.g. when the tool or the Codacy patterns of its rules changed
+if [ "$UPDATE_EXPECTED" = "1" ]; then
+  sort_sarif actual.sarif "$EXPECTED_SARIF"
+  echo "✅ Updated $EXPECTED_SARIF"
+  cd ../../../../.. || exit 1
+  exit 0
+fi
 sort_sarif "$EXPECTED_SARIF" expected.sorted.json
 sort_sarif actual.sarif actual.sorted.json
 normalize_paths expected.sorted.json
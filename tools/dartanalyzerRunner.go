@@ -4,11 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"codacy/cli-v2/constants"
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -16,43 +15,54 @@ import (
 
 const patternPrefix = "dartanalyzer_"
 
-func RunDartAnalyzer(workDirectory string, installationDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type dartAnalyzerRunner struct{}
+
+func init() {
+	RegisterRunner(dartAnalyzerRunner{})
+}
+
+func (dartAnalyzerRunner) Name() string {
+	return "dartanalyzer"
+}
+
+// Run runs the Dart analyzer, converting its machine output to SARIF when needed
+func (r dartAnalyzerRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
 
 	configFiles := []string{"analysis_options.yaml", "analysis_options.yml"}
-	dartAnalyzerPath := filepath.Join(installationDirectory, "bin", "dart")
+	dartAnalyzerPath := filepath.Join(req.Tool.InstallDir, "bin", "dart")
 
 	args := []string{"analyze", "--format", "machine"}
 	// Add files to analyze - if no files specified, analyze current directory
-	if len(files) > 0 {
-		args = append(args, files...)
+	if len(req.PathsToCheck) > 0 {
+		args = append(args, req.PathsToCheck...)
 	} else {
 		args = append(args, ".")
 	}
 
-	cmd := exec.Command(dartAnalyzerPath, args...)
+	cmd := NewToolCommand(ctx, dartAnalyzerPath, args...)
 
-	cmd.Dir = workDirectory
+	cmd.Dir = req.WorkDirectory
 
 	// Check if any config file exists
 	configExists := false
 	for _, configFile := range configFiles {
-		if _, err := os.Stat(filepath.Join(workDirectory, configFile)); err == nil {
+		if _, err := os.Stat(filepath.Join(req.WorkDirectory, configFile)); err == nil {
 			configExists = true
 			break
 		}
 	}
 
 	if !configExists {
-		log.Println("No config file found, using tool defaults")
+		fmt.Fprintln(req.Stderr, "No config file found, using tool defaults")
 	} else {
-		log.Println("Config file found, using it")
+		fmt.Fprintln(req.Stderr, "Config file found, using it")
 	}
 
 	// For SARIF output, we need to capture the output and transform it
-	if outputFormat == "sarif" {
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+	if req.OutputFormat == "sarif" {
+		var machineOutput, machineErrors bytes.Buffer
+		cmd.Stdout = &machineOutput
+		cmd.Stderr = &machineErrors
 
 		cmd.Run()
 
@@ -73,7 +83,7 @@ func RunDartAnalyzer(workDirectory string, installationDirectory string, binary
 
 		// Parse Dart Analyzer output and convert to SARIF
 		// Format is typically: file:line:col: severity: message
-		scanner := bufio.NewScanner(strings.NewReader(stdout.String()))
+		scanner := bufio.NewScanner(strings.NewReader(machineOutput.String()))
 		for scanner.Scan() {
 			line := scanner.Text()
 			if line == "" {
@@ -120,20 +130,20 @@ func RunDartAnalyzer(workDirectory string, installationDirectory string, binary
 		}
 
 		// Write SARIF output to file if specified
-		if outputFile != "" {
+		if req.OutputFile != "" {
 			sarifJson, _ := json.MarshalIndent(sarif, "", "  ")
-			err := os.WriteFile(outputFile, sarifJson, constants.DefaultFilePerms)
+			err := os.WriteFile(req.OutputFile, sarifJson, constants.DefaultFilePerms)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing SARIF output: %v\n", err)
+				fmt.Fprintf(req.Stderr, "Error writing SARIF output: %v\n", err)
 			}
 		}
-		cmd.Stderr = os.Stderr
-		cmd.Stdout = os.Stdout
+		cmd.Stderr = req.Stderr
+		cmd.Stdout = req.Stdout
 		cmd.Run()
 	} else {
-		cmd.Stderr = os.Stderr
-		cmd.Stdout = os.Stdout
+		cmd.Stderr = req.Stderr
+		cmd.Stdout = req.Stdout
 		cmd.Run()
 	}
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
@@ -1,64 +1,74 @@
 package tools
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 )
 
-func RunEnigma(workDirectory string, installationDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type enigmaRunner struct{}
+
+func init() {
+	RegisterRunner(enigmaRunner{})
+}
+
+func (enigmaRunner) Name() string {
+	return "codacy-enigma-cli"
+}
+
+// Run runs the Codacy Enigma CLI
+func (r enigmaRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
 
 	configFiles := []string{"enigma.yaml", "enigma.yml"}
 
+	outputFormat := req.OutputFormat
 	if outputFormat == "" {
 		outputFormat = "text"
 	}
 
 	args := []string{"analyze", "--format", outputFormat}
 
-	if len(files) > 0 {
-		args = append(args, append([]string{"--paths"}, files...)...)
+	if len(req.PathsToCheck) > 0 {
+		args = append(args, append([]string{"--paths"}, req.PathsToCheck...)...)
 	} else {
 		args = append(args, "--paths", ".")
 	}
 
 	configExists := ""
 	for _, configFile := range configFiles {
-		if _, err := os.Stat(filepath.Join(workDirectory, configFile)); err == nil {
-			configExists = filepath.Join(workDirectory, configFile)
+		if _, err := os.Stat(filepath.Join(req.WorkDirectory, configFile)); err == nil {
+			configExists = filepath.Join(req.WorkDirectory, configFile)
 			break
 		}
 	}
 
 	if configExists != "" {
-		log.Println("Config file found, using it")
+		fmt.Fprintln(req.Stderr, "Config file found, using it")
 		args = append(args, "--configuration-file", configExists)
 	} else {
-		log.Println("No config file found, using tool defaults")
-
+		fmt.Fprintln(req.Stderr, "No config file found, using tool defaults")
 	}
 
-	cmd := exec.Command(binary, args...)
-	cmd.Dir = workDirectory
-	cmd.Stderr = os.Stderr
-	if outputFile != "" {
+	cmd := NewToolCommand(ctx, req.Tool.Binaries["codacy-enigma-cli"], args...)
+	cmd.Dir = req.WorkDirectory
+	cmd.Stderr = req.Stderr
+	if req.OutputFile != "" {
 		// If output file is specified, create it and redirect output
 		var outputWriter *os.File
 		var err error
-		outputWriter, err = os.Create(filepath.Clean(outputFile))
+		outputWriter, err = os.Create(filepath.Clean(req.OutputFile))
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer outputWriter.Close()
 		cmd.Stdout = outputWriter
 	} else {
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = req.Stdout
 	}
 	err := cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to run Enigma: %w", err)
+		return nil, fmt.Errorf("failed to run Enigma: %w", err)
 	}
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
@@ -2,23 +2,35 @@ package tools
 
 import (
 	"codacy/cli-v2/config"
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 )
 
+type eslintRunner struct{}
+
+func init() {
+	RegisterRunner(eslintRunner{})
+}
+
+func (eslintRunner) Name() string {
+	return "eslint"
+}
+
+// Run runs ESLint
 // * Run from the root of the repo we want to analyse
 // * NODE_PATH="<the installed eslint path>/node_modules"
 // * The local installed ESLint should have the @microsoft/eslint-formatter-sarif installed
-func RunEslint(repositoryToAnalyseDirectory string, eslintInstallationDirectory string, nodeBinary string, pathsToCheck []string, autoFix bool, outputFile string, outputFormat string, usesConfigurationFile bool) error {
-	eslintInstallationNodeModules := filepath.Join(eslintInstallationDirectory, "node_modules")
+func (r eslintRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
+	nodeBinary := req.runtimeBinary(req.Tool.Runtime)
+	eslintInstallationNodeModules := filepath.Join(req.Tool.InstallDir, "node_modules")
 	eslintJsPath := filepath.Join(eslintInstallationNodeModules, ".bin", "eslint")
 
-	cmd := exec.Command(nodeBinary, eslintJsPath)
+	cmd := NewToolCommand(ctx, nodeBinary, eslintJsPath)
 
 	// Add config file from tools-configs directory if it exists
-	if !usesConfigurationFile {
+	if !req.UsesConfigurationFile {
 		if configFile, exists := ConfigFileExists(config.Config, "eslint.config.mjs"); exists {
 			// For Eslint compatibility with version 8.
 			// https://eslint.org/docs/v8.x/use/configure/configuration-files-new
@@ -28,28 +40,28 @@ func RunEslint(repositoryToAnalyseDirectory string, eslintInstallationDirectory
 		}
 	}
 
-	if autoFix {
+	if req.AutoFix {
 		cmd.Args = append(cmd.Args, "--fix")
 	}
-	if outputFormat == "sarif" {
+	if req.OutputFormat == "sarif" {
 		//When outputting in SARIF format
 		cmd.Args = append(cmd.Args, "-f", "@microsoft/eslint-formatter-sarif")
 	}
 
-	if outputFile != "" {
+	if req.OutputFile != "" {
 		//When writing to file, use the output file option
-		cmd.Args = append(cmd.Args, "-o", outputFile)
+		cmd.Args = append(cmd.Args, "-o", req.OutputFile)
 	}
 
-	if len(pathsToCheck) > 0 {
-		cmd.Args = append(cmd.Args, pathsToCheck...)
+	if len(req.PathsToCheck) > 0 {
+		cmd.Args = append(cmd.Args, req.PathsToCheck...)
 	} else {
 		cmd.Args = append(cmd.Args, ".")
 	}
 
-	cmd.Dir = repositoryToAnalyseDirectory
-	cmd.Stderr = os.Stderr
-	cmd.Stdout = os.Stdout
+	cmd.Dir = req.WorkDirectory
+	cmd.Stderr = req.Stderr
+	cmd.Stdout = req.Stdout
 
 	nodePathEnv := "NODE_PATH=" + eslintInstallationNodeModules
 	cmd.Env = append(cmd.Env, nodePathEnv)
@@ -63,9 +75,9 @@ func RunEslint(repositoryToAnalyseDirectory string, eslintInstallationDirectory
 	if err != nil {
 		// ESLint returns 1 when it finds errors, which is not a failure
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return nil
+			return NewRunResult(r.Name(), req, cmd), nil
 		}
-		return fmt.Errorf("failed to run ESLint: %w", err)
+		return nil, fmt.Errorf("failed to run ESLint: %w", err)
 	}
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
@@ -8,19 +8,34 @@ import (
 	"strings"
 )
 
+// duplicateSnippetPattern matches the places of a duplicated block, e.g. "./src/app.py:10 ~ 24"
+var duplicateSnippetPattern = regexp.MustCompile(`^(.+):(\d+) ~ (\d+)$`)
+
 // parseLizardResults parses the output from Lizard into a structured format
 func parseLizardResults(output string) (*LizardResults, error) {
 	lines := strings.Split(output, "\n")
 	results := &LizardResults{
-		Methods: make([]LizardMethod, 0),
-		Files:   make([]LizardFile, 0),
+		Methods:    make([]LizardMethod, 0),
+		Files:      make([]LizardFile, 0),
+		Duplicates: make([]LizardDuplicate, 0),
 	}
 
-	var isMethodSection, isFileSection, isWarningSection bool
+	var isMethodSection, isFileSection, isWarningSection, isDuplicateSection bool
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
+		// The duplicate extension prints its blocks last, after the warnings
+		if line == "Duplicates" {
+			isDuplicateSection = true
+			continue
+		}
+
+		if isDuplicateSection {
+			parseDuplicateLine(results, line)
+			continue
+		}
+
 		if strings.Contains(line, "!!!! Warnings") {
 			isWarningSection = true
 			continue
@@ -134,6 +149,23 @@ func parseLizardResults(output string) (*LizardResults, error) {
 	return results, nil
 }
 
+// parseDuplicateLine adds a line of the output of the duplicate extension to the duplicated blocks
+func parseDuplicateLine(results *LizardResults, line string) {
+	if line == "Duplicate block:" {
+		results.Duplicates = append(results.Duplicates, LizardDuplicate{})
+		return
+	}
+
+	match := duplicateSnippetPattern.FindStringSubmatch(line)
+	if match == nil || len(results.Duplicates) == 0 {
+		return
+	}
+	startLine, _ := strconv.Atoi(match[2])
+	endLine, _ := strconv.Atoi(match[3])
+	duplicate := &results.Duplicates[len(results.Duplicates)-1]
+	duplicate.Snippets = append(duplicate.Snippets, LizardSnippet{File: match[1], StartLine: startLine, EndLine: endLine})
+}
+
 // generateIssuesFromResults generates SARIF issues from Lizard results
 func generateIssuesFromResults(results *LizardResults, patterns []domain.PatternDefinition) []Issue {
 	var issues []Issue
@@ -0,0 +1,70 @@
+package lizard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lizardOutputWithDuplicates = `================================================
+  NLOC    CCN   token  PARAM  length  location
+------------------------------------------------
+       3      2     12      1       3 add@1-3@./src/a.py
+       3      2     12      1       3 add@1-3@./src/b.py
+2 file analyzed.
+==============================================================
+NLOC    Avg.NLOC  AvgCCN  Avg.token  function_cnt    file
+--------------------------------------------------------------
+      3       3.0     2.0       12.0         1     ./src/a.py
+      3       3.0     2.0       12.0         1     ./src/b.py
+Duplicates
+===================================
+Duplicate block:
+--------------------------
+./src/a.py:1 ~ 3
+./src/b.py:1 ~ 3
+^^^^^^^^^^^^^^^^^^^^^^^^^^
+
+Total duplicate rate: 100.00%
+Total unique rate: 0.00%
+`
+
+func TestParseLizardResultsReadsDuplicates(t *testing.T) {
+	results, err := parseLizardResults(lizardOutputWithDuplicates)
+
+	require.NoError(t, err)
+	assert.Len(t, results.Methods, 2)
+	assert.Len(t, results.Files, 2)
+	assert.Equal(t, []LizardDuplicate{{Snippets: []LizardSnippet{
+		{File: "./src/a.py", StartLine: 1, EndLine: 3},
+		{File: "./src/b.py", StartLine: 1, EndLine: 3},
+	}}}, results.Duplicates)
+}
+
+func TestFileArtifactsHoldDuplicatedBlocks(t *testing.T) {
+	workDirectory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDirectory, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workDirectory, "src", "a.py"), []byte("def add(a):\n    if a:\n        return a\n"), 0644))
+	results, err := parseLizardResults(lizardOutputWithDuplicates)
+	require.NoError(t, err)
+
+	artifacts := fileArtifacts(results, workDirectory)
+
+	require.Len(t, artifacts, 2)
+	block := domain.DuplicatedBlock{
+		Lines: "def add(a):\n    if a:\n        return a",
+		Locations: []domain.DuplicatedLocation{
+			{Path: "src/a.py", StartLine: 1, EndLine: 3},
+			{Path: "src/b.py", StartLine: 1, EndLine: 3},
+		},
+	}
+	for _, artifact := range artifacts {
+		assert.Equal(t, 2, artifact.Properties.Metrics.MaxCcn)
+		assert.Equal(t, []domain.DuplicatedBlock{block}, artifact.Properties.Metrics.Duplication, artifact.Location.URI)
+	}
+}
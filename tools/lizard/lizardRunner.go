@@ -7,16 +7,26 @@ import (
 	"codacy/cli-v2/domain"
 	"codacy/cli-v2/tools"
 	"codacy/cli-v2/utils/logger"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/sirupsen/logrus"
 )
 
-// RunLizard runs the Lizard tool and returns any issues found
-func RunLizard(workDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type lizardRunner struct{}
+
+func init() {
+	tools.RegisterRunner(lizardRunner{})
+}
+
+func (lizardRunner) Name() string {
+	return "lizard"
+}
+
+// Run runs the Lizard tool and reports the methods exceeding the configured thresholds as issues
+func (r lizardRunner) Run(ctx context.Context, req *tools.RunRequest) (*tools.RunResult, error) {
 	// Get configuration patterns
 	configFile, exists := tools.ConfigFileExists(config.Config, "lizard.yaml")
 	var patterns []domain.PatternDefinition
@@ -26,100 +36,104 @@ func RunLizard(workDirectory string, binary string, files []string, outputFile s
 		// Configuration exists, read from file
 		patterns, errConfigs = ReadConfig(configFile)
 		if errConfigs != nil {
-			return fmt.Errorf("error reading config file: %v", errConfigs)
+			return nil, fmt.Errorf("error reading config file: %v", errConfigs)
 		}
 	} else {
-		fmt.Println("No configuration file found for Lizard, using default patterns, run init with repository token to get a custom configuration")
+		fmt.Fprintln(req.Stdout, "No configuration file found for Lizard, using default patterns, run init with repository token to get a custom configuration")
 		patterns, errConfigs = tools.FetchDefaultEnabledPatterns(domain.Lizard)
 		if errConfigs != nil {
-			return fmt.Errorf("failed to fetch default patterns: %v", errConfigs)
+			return nil, fmt.Errorf("failed to fetch default patterns: %v", errConfigs)
 		}
 	}
 
 	if len(patterns) == 0 {
-		return fmt.Errorf("no valid patterns found in configuration")
+		return nil, fmt.Errorf("no valid patterns found in configuration")
 	}
 	// Construct base command with lizard module
 	args := []string{"-m", "lizard", "-V"}
+	// Duplicated blocks are reported in the metrics of the SARIF artifacts
+	if req.OutputFormat == "sarif" {
+		args = append(args, "-Eduplicate")
+	}
 
 	// Add files to analyze - if no files specified, analyze current directory
-	if len(files) > 0 {
-		args = append(args, files...)
+	if len(req.PathsToCheck) > 0 {
+		args = append(args, req.PathsToCheck...)
 	} else {
 		args = append(args, ".")
 	}
 
 	// For non-SARIF output, let Lizard handle file output directly
-	if outputFormat != "sarif" && outputFile != "" {
-		args = append(args, "-o", outputFile)
+	if req.OutputFormat != "sarif" && req.OutputFile != "" {
+		args = append(args, "-o", req.OutputFile)
 	}
 
 	// Run the command
-	cmd := exec.Command(binary, args...)
-	cmd.Dir = workDirectory
+	cmd := tools.NewToolCommand(ctx, req.Tool.Binaries[req.Tool.Runtime], args...)
+	cmd.Dir = req.WorkDirectory
 
 	var err error
-	var stderr bytes.Buffer
+	var lizardErrors bytes.Buffer
 
-	cmd.Stderr = &stderr
+	cmd.Stderr = &lizardErrors
 
 	// For SARIF output, we need to capture and parse the output
-	if outputFormat == "sarif" {
-		var stdout bytes.Buffer
-		cmd.Stdout = &stdout
+	if req.OutputFormat == "sarif" {
+		var lizardOutput bytes.Buffer
+		cmd.Stdout = &lizardOutput
 
 		err = cmd.Run()
 
-		if stderr.Len() > 0 && err != nil {
+		if lizardErrors.Len() > 0 && err != nil {
 			logger.Debug("Failed to run Lizard: ", logrus.Fields{
 				"error":  err.Error(),
-				"stderr": string(stderr.Bytes()),
+				"stderr": string(lizardErrors.Bytes()),
 			})
 
-			return fmt.Errorf("failed to run Lizard: %w", err)
+			return nil, fmt.Errorf("failed to run Lizard: %w", err)
 		}
 
 		// Parse the output and generate issues
-		results, parseErr := parseLizardResults(stdout.String())
+		results, parseErr := parseLizardResults(lizardOutput.String())
 		if parseErr != nil {
-			return fmt.Errorf("failed to parse Lizard output: %w", parseErr)
+			return nil, fmt.Errorf("failed to parse Lizard output: %w", parseErr)
 		}
 		issues := generateIssuesFromResults(results, patterns)
 
 		// Convert issues to SARIF Report
-		sarifReport := convertIssuesToSarif(issues, patterns)
+		sarifReport := convertIssuesToSarif(issues, patterns, fileArtifacts(results, req.WorkDirectory), req.WorkDirectory)
 		// Marshal SARIF Report report to Sarif
 		sarifData, err := json.MarshalIndent(sarifReport, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to marshal SARIF report: %w", err)
+			return nil, fmt.Errorf("failed to marshal SARIF report: %w", err)
 		}
 
 		// Write SARIF output to file if specified, else stdout
-		if outputFile != "" {
-			err = os.WriteFile(outputFile, sarifData, constants.DefaultFilePerms)
+		if req.OutputFile != "" {
+			err = os.WriteFile(req.OutputFile, sarifData, constants.DefaultFilePerms)
 			if err != nil {
-				return fmt.Errorf("failed to write SARIF output: %w", err)
+				return nil, fmt.Errorf("failed to write SARIF output: %w", err)
 			}
 		} else {
-			fmt.Println(string(sarifData))
+			fmt.Fprintln(req.Stdout, string(sarifData))
 		}
 
-		return nil
+		return tools.NewRunResult(r.Name(), req, cmd), nil
 
 	} else {
 		// For non-SARIF output, let Lizard handle stdout
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = req.Stdout
 		err = cmd.Run()
 
-		if stderr.Len() > 0 && err != nil {
+		if lizardErrors.Len() > 0 && err != nil {
 			logger.Debug("Failed to run Lizard: ", logrus.Fields{
 				"error":  err.Error(),
-				"stderr": string(stderr.Bytes()),
+				"stderr": string(lizardErrors.Bytes()),
 			})
 
-			return fmt.Errorf("failed to run Lizard: %w", err)
+			return nil, fmt.Errorf("failed to run Lizard: %w", err)
 		}
 	}
 
-	return nil
+	return tools.NewRunResult(r.Name(), req, cmd), nil
 }
@@ -3,13 +3,14 @@ package lizard
 import (
 	"codacy/cli-v2/domain"
 	"codacy/cli-v2/utils"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 )
 
-// convertIssuesToSarif converts Lizard issues to SARIF Report
-func convertIssuesToSarif(issues []Issue, patterns []domain.PatternDefinition) *utils.SarifReport {
+// convertIssuesToSarif converts Lizard issues to SARIF Report, with the analyzed files and their metrics as artifacts
+func convertIssuesToSarif(issues []Issue, patterns []domain.PatternDefinition, artifacts []utils.Artifact, workDirectory string) *utils.SarifReport {
 	// Create a map to track unique rules
 	rules := make(map[string]utils.Rule)
 
@@ -30,8 +31,8 @@ func convertIssuesToSarif(issues []Issue, patterns []domain.PatternDefinition) *
 
 	// Process each issue
 	for _, issue := range issues {
-		// Get the base filename for the file path
-		filePath := filepath.Base(issue.File)
+		// Use the path relative to the work directory, like the artifacts, so results are cached per file
+		filePath := utils.NormalizeSarifURI(workDirectory, issue.File)
 
 		// Create the result
 		result := utils.Result{
@@ -82,8 +83,83 @@ func convertIssuesToSarif(issues []Issue, patterns []domain.PatternDefinition) *
 						Rules:          ruleSlice,
 					},
 				},
-				Results: results,
+				Artifacts: artifacts,
+				Results:   results,
 			},
 		},
 	}
 }
+
+// fileArtifacts lists the files analyzed by Lizard with their metrics, computed from the metrics of their
+// functions, and their duplicated blocks. Paths are made relative to workDirectory.
+func fileArtifacts(results *LizardResults, workDirectory string) []utils.Artifact {
+	methodsByFile := make(map[string][]LizardMethod)
+	for _, method := range results.Methods {
+		methodsByFile[method.File] = append(methodsByFile[method.File], method)
+	}
+	duplicationByFile := duplicatedBlocksByFile(results.Duplicates, workDirectory)
+
+	artifacts := make([]utils.Artifact, 0, len(results.Files))
+	for _, file := range results.Files {
+		metrics := &domain.FileMetrics{Nloc: file.Nloc, MaxCcn: file.MaxCcn, Functions: file.MethodsCount}
+		for _, method := range methodsByFile[file.File] {
+			metrics.Ccn += method.Ccn
+			metrics.LineComplexities = append(metrics.LineComplexities, domain.LineComplexity{Line: method.FromLine, Value: method.Ccn})
+		}
+		metrics.Duplication = duplicationByFile[file.File]
+		artifacts = append(artifacts, utils.Artifact{
+			Location:   utils.ArtifactLocation{URI: utils.NormalizeSarifURI(workDirectory, file.File)},
+			Properties: &utils.ArtifactProperties{Metrics: metrics},
+		})
+	}
+	return artifacts
+}
+
+// duplicatedBlocksByFile groups the duplicated blocks by each file they are found in, keyed by the file name
+// reported by Lizard. Each block holds the code of its first location, read from workDirectory.
+func duplicatedBlocksByFile(duplicates []LizardDuplicate, workDirectory string) map[string][]domain.DuplicatedBlock {
+	fileLines := make(map[string][]string)
+	blocksByFile := make(map[string][]domain.DuplicatedBlock)
+	for _, duplicate := range duplicates {
+		if len(duplicate.Snippets) < 2 {
+			continue
+		}
+
+		block := domain.DuplicatedBlock{Lines: snippetLines(fileLines, workDirectory, duplicate.Snippets[0])}
+		for _, snippet := range duplicate.Snippets {
+			block.Locations = append(block.Locations, domain.DuplicatedLocation{
+				Path:      utils.NormalizeSarifURI(workDirectory, snippet.File),
+				StartLine: snippet.StartLine,
+				EndLine:   snippet.EndLine,
+			})
+		}
+
+		added := make(map[string]bool)
+		for _, snippet := range duplicate.Snippets {
+			if !added[snippet.File] {
+				added[snippet.File] = true
+				blocksByFile[snippet.File] = append(blocksByFile[snippet.File], block)
+			}
+		}
+	}
+	return blocksByFile
+}
+
+// snippetLines returns the code of a snippet, reading each file once. It is empty when the file can't be read.
+func snippetLines(fileLines map[string][]string, workDirectory string, snippet LizardSnippet) string {
+	lines, ok := fileLines[snippet.File]
+	if !ok {
+		path := snippet.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDirectory, path)
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		fileLines[snippet.File] = lines
+	}
+	if snippet.StartLine < 1 || snippet.EndLine < snippet.StartLine || snippet.EndLine > len(lines) {
+		return ""
+	}
+	return strings.Join(lines[snippet.StartLine-1:snippet.EndLine], "\n")
+}
@@ -1,7 +1,10 @@
 package test
 
 import (
-	"codacy/cli-v2/tools/lizard"
+	"codacy/cli-v2/plugins"
+	"codacy/cli-v2/tools"
+	_ "codacy/cli-v2/tools/lizard"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -42,9 +45,25 @@ func TestRunLizardWithSarifOutput(t *testing.T) {
 	expectedOutput := strings.TrimSpace(string(expectedData))
 
 	// Run Lizard with SARIF output
-	err = lizard.RunLizard(currentDir, lizardBinary, []string{complexPyPath}, outputFile, "sarif")
+	runner, ok := tools.GetRunner("lizard")
+	if !ok {
+		t.Fatal("Lizard runner is not registered")
+	}
+	_, err = runner.Run(context.Background(), &tools.RunRequest{
+		WorkDirectory: currentDir,
+		PathsToCheck:  []string{complexPyPath},
+		OutputFile:    outputFile,
+		OutputFormat:  "sarif",
+		Tool: &plugins.ToolInfo{
+			Name:     "lizard",
+			Runtime:  "python",
+			Binaries: map[string]string{"python": lizardBinary},
+		},
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
 	if err != nil {
-		t.Fatalf("RunLizard failed: %v", err)
+		t.Fatalf("Lizard runner failed: %v", err)
 	}
 
 	// Read and parse the SARIF output
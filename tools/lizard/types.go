@@ -34,8 +34,21 @@ type LizardFile struct {
 	MethodsCount  int     `json:"methodsCount"`
 }
 
+// LizardDuplicate represents a block of code found in several places by the duplicate extension of Lizard
+type LizardDuplicate struct {
+	Snippets []LizardSnippet `json:"snippets"`
+}
+
+// LizardSnippet represents a place where a duplicated block of code is found
+type LizardSnippet struct {
+	File      string `json:"file"`
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+}
+
 // LizardResults represents the parsed output from Lizard
 type LizardResults struct {
-	Methods []LizardMethod `json:"methods"`
-	Files   []LizardFile   `json:"files"`
+	Methods    []LizardMethod    `json:"methods"`
+	Files      []LizardFile      `json:"files"`
+	Duplicates []LizardDuplicate `json:"duplicates"`
 }
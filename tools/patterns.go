@@ -1,6 +1,7 @@
 package tools
 
 import (
+	codacyclient "codacy/cli-v2/codacy-client"
 	"codacy/cli-v2/domain"
 	"encoding/json"
 	"fmt"
@@ -16,7 +17,7 @@ func FetchDefaultEnabledPatterns(toolUUID string) ([]domain.PatternDefinition, e
 	}
 
 	// Fetch default patterns from Codacy API
-	url := fmt.Sprintf("https://app.codacy.com/api/v3/tools/%s/patterns", toolUUID)
+	url := fmt.Sprintf("%s/api/v3/tools/%s/patterns", codacyclient.CodacyApiBase, toolUUID)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -3,6 +3,7 @@ package tools
 import (
 	"codacy/cli-v2/config"
 	"codacy/cli-v2/utils/logger"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,20 +14,24 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// RunPmd executes PMD static code analyzer with the specified options
-//
-// Parameters:
-//   - repositoryToAnalyseDirectory: The root directory of the repository to analyze
-//   - pmdBinary: Path to the PMD executable
-//   - pathsToCheck: List of specific paths to analyze, if empty analyzes whole repository
-//   - outputFile: Path where analysis results should be written
-//   - outputFormat: Format for the output (e.g. "sarif")
-//   - config: Configuration object containing tool info
+type pmdRunner struct{}
+
+func init() {
+	RegisterRunner(pmdRunner{})
+}
+
+func (pmdRunner) Name() string {
+	return "pmd"
+}
+
+// Run executes PMD static code analyzer with the specified options.
+// The Java runtime is taken from the Runtime of the request.
 //
 // Returns:
 //   - error: nil if analysis succeeds or violations found, error otherwise
-func RunPmd(repositoryToAnalyseDirectory string, pmdBinary string, pathsToCheck []string, outputFile string, outputFormat string, config config.ConfigType) error {
+func (r pmdRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
 	var cmd *exec.Cmd
+	pmdBinary := req.Tool.Binaries["pmd"]
 
 	// Debug: Log the binary path being used
 	logger.Debug("PMD binary path", logrus.Fields{
@@ -39,65 +44,58 @@ func RunPmd(repositoryToAnalyseDirectory string, pmdBinary string, pathsToCheck
 			"pmdBinary": pmdBinary,
 			"error":     err,
 		})
-		return fmt.Errorf("PMD binary not found at %s: %w", pmdBinary, err)
-	}
-
-	// Get tool info to check version
-	toolInfo := config.Tools()["pmd"]
-	if toolInfo == nil {
-		logger.Warn("PMD tool info not found in configuration")
-		return fmt.Errorf("pmd tool info not found in configuration")
+		return nil, fmt.Errorf("PMD binary not found at %s: %w", pmdBinary, err)
 	}
 
 	// Check if we're using a newer version (7.0.0+)
-	isNewVersion := toolInfo.Version >= "7.0.0"
+	isNewVersion := req.Tool.Version >= "7.0.0"
 
 	if isNewVersion {
 		// For newer versions (7.0.0+), use the binary with 'check' command
-		cmd = exec.Command(pmdBinary, "check", "--no-fail-on-violation")
+		cmd = NewToolCommand(ctx, pmdBinary, "check", "--no-fail-on-violation")
 	} else {
 		// For older versions, use "pmd" subcommand
 		if runtime.GOOS == "windows" {
-			cmd = exec.Command(pmdBinary) // On Windows, don't add "pmd" subcommand
+			cmd = NewToolCommand(ctx, pmdBinary) // On Windows, don't add "pmd" subcommand
 		} else {
-			cmd = exec.Command(pmdBinary, "pmd") // On Unix, use "pmd" subcommand
+			cmd = NewToolCommand(ctx, pmdBinary, "pmd") // On Unix, use "pmd" subcommand
 		}
 	}
 
 	// Add config file from tools-configs directory if it exists
-	if configFile, exists := ConfigFileExists(config, "ruleset.xml"); exists {
+	if configFile, exists := ConfigFileExists(config.Config, "ruleset.xml"); exists {
 		cmd.Args = append(cmd.Args, "-R", configFile)
 	}
 
 	// Add source directories (comma-separated list for PMD)
-	if len(pathsToCheck) > 0 {
-		dirArg := strings.Join(pathsToCheck, ",")
+	if len(req.PathsToCheck) > 0 {
+		dirArg := strings.Join(req.PathsToCheck, ",")
 		cmd.Args = append(cmd.Args, "-d", dirArg)
 	} else {
 		// Fall back to whole repo if no specific paths given
-		cmd.Args = append(cmd.Args, "-d", repositoryToAnalyseDirectory)
+		cmd.Args = append(cmd.Args, "-d", req.WorkDirectory)
 	}
 
 	// Format
-	if outputFormat != "" {
-		cmd.Args = append(cmd.Args, "-f", outputFormat)
+	if req.OutputFormat != "" {
+		cmd.Args = append(cmd.Args, "-f", req.OutputFormat)
 	}
 
 	// Output file
-	if outputFile != "" {
-		cmd.Args = append(cmd.Args, "-r", outputFile)
+	if req.OutputFile != "" {
+		cmd.Args = append(cmd.Args, "-r", req.OutputFile)
 		// When storing results in a file, all the logs output should go to stderr
 		// Note that for formats like SARIF, tools output their results to a temporary file
-		cmd.Stdout = os.Stderr
+		cmd.Stdout = req.Stderr
 	} else {
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = req.Stdout
 	}
 
-	cmd.Stderr = os.Stderr
-	cmd.Dir = repositoryToAnalyseDirectory
+	cmd.Stderr = req.Stderr
+	cmd.Dir = req.WorkDirectory
 
-	// Get Java runtime info
-	javaRuntime := config.Runtimes()["java"]
+	// PMD runs on the Java runtime of the request
+	javaRuntime := req.Runtime
 	if javaRuntime != nil {
 		logger.Debug("Setting up Java environment", logrus.Fields{
 			"javaHome": javaRuntime.InstallDir,
@@ -123,8 +121,8 @@ func RunPmd(repositoryToAnalyseDirectory string, pmdBinary string, pathsToCheck
 
 			// Not throwing - Fallback to the default Java runtime
 			// This fallback going to be removed in the future https://codacy.atlassian.net/browse/PLUTO-1421
-			fmt.Printf("⚠️ Warning: Java binary not found at %s: %v\n", javaBinary, err)
-			fmt.Println("⚠️ Trying to continue with the default Java runtime")
+			fmt.Fprintf(req.Stderr, "⚠️ Warning: Java binary not found at %s: %v\n", javaBinary, err)
+			fmt.Fprintln(req.Stderr, "⚠️ Trying to continue with the default Java runtime")
 			logger.Warn("Java binary not found. Continuing with the default Java runtime", logrus.Fields{
 				"expectedPath": javaBinary,
 				"error":        err,
@@ -158,7 +156,7 @@ func RunPmd(repositoryToAnalyseDirectory string, pmdBinary string, pathsToCheck
 
 	} else {
 		logger.Warn("Java runtime not found in configuration")
-		return fmt.Errorf("java runtime not found in configuration")
+		return nil, fmt.Errorf("java runtime not found in configuration")
 	}
 
 	logger.Debug("Running PMD command", logrus.Fields{
@@ -169,9 +167,9 @@ func RunPmd(repositoryToAnalyseDirectory string, pmdBinary string, pathsToCheck
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 4 {
 			// Exit code 4 means violations found – treat as success
-			return nil
+			return NewRunResult(r.Name(), req, cmd), nil
 		}
-		return fmt.Errorf("failed to run PMD: %w", err)
+		return nil, fmt.Errorf("failed to run PMD: %w", err)
 	}
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// processWaitDelay is how long a cancelled tool may keep its output pipes open after being killed
+const processWaitDelay = 5 * time.Second
+
+// NewToolCommand creates the command of a tool process. The process runs in its own process group,
+// and the whole group is killed when the context is done, so no child process outlives a cancelled
+// or timed out analysis.
+func NewToolCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
+	}
+	cmd.WaitDelay = processWaitDelay
+	return cmd
+}
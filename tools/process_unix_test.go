@@ -0,0 +1,40 @@
+//go:build !windows
+
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToolCommandKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// The shell starts a child process that would keep running if only the shell was killed
+	cmd := NewToolCommand(ctx, "sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	start := time.Now()
+	err := cmd.Run()
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	childPid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(childPid, 0) != nil
+	}, 5*time.Second, 50*time.Millisecond, "child process should be killed with its group")
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package tools
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows, where process groups are not used
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the process. Child processes are not tracked on Windows.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -4,12 +4,24 @@ import (
 	"codacy/cli-v2/config"
 	"codacy/cli-v2/constants"
 	"codacy/cli-v2/utils"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 )
 
-func RunPylint(workDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type pylintRunner struct{}
+
+func init() {
+	RegisterRunner(pylintRunner{})
+}
+
+func (pylintRunner) Name() string {
+	return "pylint"
+}
+
+// Run runs Pylint, converting its JSON output to SARIF when needed
+func (r pylintRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
 
 	// Construct base command with -m pylint to run pylint module
 	args := []string{"-m", "pylint"}
@@ -24,31 +36,31 @@ func RunPylint(workDirectory string, binary string, files []string, outputFile s
 
 	// Create a temporary file for JSON output if we need to convert to SARIF
 	var tempFile string
-	if outputFormat == "sarif" {
+	if req.OutputFormat == "sarif" {
 		tmp, err := os.CreateTemp("", "pylint-*.json")
 		if err != nil {
-			return fmt.Errorf("failed to create temporary file: %w", err)
+			return nil, fmt.Errorf("failed to create temporary file: %w", err)
 		}
 		tempFile = tmp.Name()
 		tmp.Close()
 		defer os.Remove(tempFile)
 		args = append(args, fmt.Sprintf("--output=%s", tempFile))
-	} else if outputFile != "" {
-		args = append(args, fmt.Sprintf("--output=%s", outputFile))
+	} else if req.OutputFile != "" {
+		args = append(args, fmt.Sprintf("--output=%s", req.OutputFile))
 	}
 
 	// Add files to analyze - if no files specified, analyze current directory
-	if len(files) > 0 {
-		args = append(args, files...)
+	if len(req.PathsToCheck) > 0 {
+		args = append(args, req.PathsToCheck...)
 	} else {
 		args = append(args, ".")
 	}
 
 	// Create and run command
-	cmd := exec.Command(binary, args...)
-	cmd.Dir = workDirectory
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd := NewToolCommand(ctx, req.Tool.Binaries[req.Tool.Runtime], args...)
+	cmd.Dir = req.WorkDirectory
+	cmd.Stdout = req.Stdout
+	cmd.Stderr = req.Stderr
 
 	// Run the command
 	err := cmd.Run()
@@ -56,28 +68,28 @@ func RunPylint(workDirectory string, binary string, files []string, outputFile s
 		// Pylint returns non-zero exit code when it finds issues
 		// We should not treat this as an error
 		if _, ok := err.(*exec.ExitError); !ok {
-			return fmt.Errorf("failed to run Pylint: %w", err)
+			return nil, fmt.Errorf("failed to run Pylint: %w", err)
 		}
 	}
 
 	// If SARIF output is requested, convert JSON to SARIF
-	if outputFormat == "sarif" {
+	if req.OutputFormat == "sarif" {
 		jsonOutput, err := os.ReadFile(tempFile)
 		if err != nil {
-			return fmt.Errorf("failed to read pylint output: %w", err)
+			return nil, fmt.Errorf("failed to read pylint output: %w", err)
 		}
 
 		sarifOutput := utils.ConvertPylintToSarif(jsonOutput)
 
-		if outputFile != "" {
-			err = os.WriteFile(outputFile, sarifOutput, constants.DefaultFilePerms)
+		if req.OutputFile != "" {
+			err = os.WriteFile(req.OutputFile, sarifOutput, constants.DefaultFilePerms)
 			if err != nil {
-				return fmt.Errorf("failed to write SARIF output: %w", err)
+				return nil, fmt.Errorf("failed to write SARIF output: %w", err)
 			}
 		} else {
-			fmt.Println(string(sarifOutput))
+			fmt.Fprintln(req.Stdout, string(sarifOutput))
 		}
 	}
 
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
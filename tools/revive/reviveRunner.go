@@ -4,50 +4,60 @@ import (
 	"codacy/cli-v2/config"
 	parenttools "codacy/cli-v2/tools"
 	"codacy/cli-v2/utils/logger"
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 
 	"github.com/sirupsen/logrus"
 )
 
-// RunRevive executes revive analysis on the specified files or directory
-func RunRevive(workDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type reviveRunner struct{}
+
+func init() {
+	parenttools.RegisterRunner(reviveRunner{})
+}
+
+func (reviveRunner) Name() string {
+	return "revive"
+}
+
+// Run executes revive analysis on the specified files or directory
+func (r reviveRunner) Run(ctx context.Context, req *parenttools.RunRequest) (*parenttools.RunResult, error) {
 	cmdArgs := []string{}
 
 	// Check if a config file exists in the expected location and use it if present
 	if configFile, exists := parenttools.ConfigFileExists(config.Config, "revive.toml"); exists {
-		log.Printf("[REVIVE] Using config file: %s", configFile)
+		fmt.Fprintf(req.Stderr, "[REVIVE] Using config file: %s\n", configFile)
 		cmdArgs = append(cmdArgs, "-config", configFile)
 	}
 
 	// Add output format if specified
-	if outputFormat != "" {
-		cmdArgs = append(cmdArgs, "-formatter", outputFormat)
+	if req.OutputFormat != "" {
+		cmdArgs = append(cmdArgs, "-formatter", req.OutputFormat)
 	}
 
 	// Add files to analyze - if no files specified, analyze current directory
-	if len(files) > 0 {
-		cmdArgs = append(cmdArgs, files...)
+	if len(req.PathsToCheck) > 0 {
+		cmdArgs = append(cmdArgs, req.PathsToCheck...)
 	} else {
 		cmdArgs = append(cmdArgs, "./...")
 	}
 
-	cmd := exec.Command(binary, cmdArgs...)
-	cmd.Dir = workDirectory
-	cmd.Stderr = os.Stderr
+	cmd := parenttools.NewToolCommand(ctx, req.Tool.Binaries["revive"], cmdArgs...)
+	cmd.Dir = req.WorkDirectory
+	cmd.Stderr = req.Stderr
 
 	// Handle output file redirection
-	if outputFile != "" {
-		outputWriter, err := os.Create(outputFile)
+	if req.OutputFile != "" {
+		outputWriter, err := os.Create(req.OutputFile)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer outputWriter.Close()
 		cmd.Stdout = outputWriter
 	} else {
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = req.Stdout
 	}
 
 	logger.Debug("Running Revive command", logrus.Fields{
@@ -56,13 +66,13 @@ func RunRevive(workDirectory string, binary string, files []string, outputFile s
 
 	if err := cmd.Run(); err != nil {
 		if _, ok := err.(*exec.ExitError); !ok {
-			log.Printf("[REVIVE] Error running revive: %v", err)
-			return fmt.Errorf("failed to run revive: %w", err)
+			fmt.Fprintf(req.Stderr, "[REVIVE] Error running revive: %v\n", err)
+			return nil, fmt.Errorf("failed to run revive: %w", err)
 		}
-		log.Printf("[REVIVE] revive exited with non-zero status (findings may be present)")
+		fmt.Fprintln(req.Stderr, "[REVIVE] revive exited with non-zero status (findings may be present)")
 	} else {
-		log.Printf("[REVIVE] revive completed successfully")
+		fmt.Fprintln(req.Stderr, "[REVIVE] revive completed successfully")
 	}
 
-	return nil
+	return parenttools.NewRunResult(r.Name(), req, cmd), nil
 }
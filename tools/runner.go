@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"sync"
+
+	"codacy/cli-v2/plugins"
+)
+
+// RunRequest holds everything a runner needs to analyze a repository
+type RunRequest struct {
+	// WorkDirectory is the root directory of the repository to analyze
+	WorkDirectory string
+	// PathsToCheck are the files or directories to analyze, the whole repository when empty
+	PathsToCheck []string
+	// OutputFile is where the results are written, the results go to Stdout when empty
+	OutputFile string
+	// OutputFormat is the format of the results, e.g. "sarif", or the tool's native format when empty
+	OutputFormat string
+	// AutoFix asks the tool to fix issues when possible
+	AutoFix bool
+	// Tool is the installed tool
+	Tool *plugins.ToolInfo
+	// Runtime is the runtime the tool runs on, nil for tools without a runtime
+	Runtime *plugins.RuntimeInfo
+	// UsesConfigurationFile is true when the repository has its own tool configuration file
+	UsesConfigurationFile bool
+	// Stdout and Stderr receive the tool console output
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// RunResult describes a finished tool run
+type RunResult struct {
+	// ToolName is the name of the runner that produced the result
+	ToolName string
+	// OutputFile is the file the results were written to, empty when they were written to Stdout
+	OutputFile string
+	// ExitCode is the exit code of the tool process, -1 when the process didn't run
+	ExitCode int
+}
+
+// ToolRunner runs a tool against a repository
+type ToolRunner interface {
+	// Name returns the tool name, as used in codacy.yaml
+	Name() string
+	// Run analyzes the repository as described by the request. Issues found are not an error.
+	Run(ctx context.Context, req *RunRequest) (*RunResult, error)
+}
+
+var (
+	runnersMutex sync.RWMutex
+	runners      = make(map[string]ToolRunner)
+)
+
+// RegisterRunner makes a runner available by its name. It is meant to be called from init functions
+// and panics if a runner with the same name is already registered.
+func RegisterRunner(runner ToolRunner) {
+	runnersMutex.Lock()
+	defer runnersMutex.Unlock()
+
+	if _, exists := runners[runner.Name()]; exists {
+		panic(fmt.Sprintf("tool runner %s is already registered", runner.Name()))
+	}
+	runners[runner.Name()] = runner
+}
+
+// GetRunner returns the runner registered for a tool
+func GetRunner(toolName string) (ToolRunner, bool) {
+	runnersMutex.RLock()
+	defer runnersMutex.RUnlock()
+
+	runner, ok := runners[toolName]
+	return runner, ok
+}
+
+// RegisteredRunners returns the sorted names of all registered runners
+func RegisteredRunners() []string {
+	runnersMutex.RLock()
+	defer runnersMutex.RUnlock()
+
+	names := make([]string, 0, len(runners))
+	for name := range runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewRunResult creates the result of a run from the command that was executed
+func NewRunResult(toolName string, req *RunRequest, cmd *exec.Cmd) *RunResult {
+	exitCode := -1
+	if cmd != nil && cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	return &RunResult{ToolName: toolName, OutputFile: req.OutputFile, ExitCode: exitCode}
+}
+
+// runtimeBinary returns the path of a runtime binary, or an empty string when the request has no runtime
+func (req *RunRequest) runtimeBinary(name string) string {
+	if req.Runtime == nil {
+		return ""
+	}
+	return req.Runtime.Binaries[name]
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRunner struct {
+	name string
+}
+
+func (f fakeRunner) Name() string {
+	return f.name
+}
+
+func (f fakeRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
+	return NewRunResult(f.name, req, nil), nil
+}
+
+func TestRegisterRunner(t *testing.T) {
+	runner := fakeRunner{name: "fake-tool"}
+	RegisterRunner(runner)
+	defer func() {
+		runnersMutex.Lock()
+		delete(runners, runner.name)
+		runnersMutex.Unlock()
+	}()
+
+	registered, ok := GetRunner("fake-tool")
+	require.True(t, ok)
+	assert.Equal(t, runner, registered)
+	assert.Contains(t, RegisteredRunners(), "fake-tool")
+
+	assert.Panics(t, func() { RegisterRunner(fakeRunner{name: "fake-tool"}) })
+
+	_, ok = GetRunner("unknown-tool")
+	assert.False(t, ok)
+}
+
+func TestBuiltInRunnersAreRegistered(t *testing.T) {
+	for _, name := range []string{"eslint", "trivy", "pmd", "pylint", "dartanalyzer", "opengrep", "codacy-enigma-cli"} {
+		_, ok := GetRunner(name)
+		assert.True(t, ok, "runner for %s should be registered", name)
+	}
+}
+
+func TestNewRunResult(t *testing.T) {
+	req := &RunRequest{OutputFile: "results.sarif"}
+
+	result := NewRunResult("fake-tool", req, nil)
+	assert.Equal(t, &RunResult{ToolName: "fake-tool", OutputFile: "results.sarif", ExitCode: -1}, result)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	cmd := exec.Command("sh", "-c", "exit 3")
+	_ = cmd.Run()
+	assert.Equal(t, 3, NewRunResult("fake-tool", req, cmd).ExitCode)
+}
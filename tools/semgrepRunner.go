@@ -2,14 +2,25 @@ package tools
 
 import (
 	"codacy/cli-v2/config"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 )
 
-// RunOpengrep executes Opengrep analysis on the specified directory
-func RunOpengrep(workDirectory string, binary string, files []string, outputFile string, outputFormat string) error {
+type opengrepRunner struct{}
+
+func init() {
+	RegisterRunner(opengrepRunner{})
+}
+
+func (opengrepRunner) Name() string {
+	return "opengrep"
+}
+
+// Run executes Opengrep analysis on the specified directory
+func (r opengrepRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
 	cmdArgs := []string{"scan"}
 
 	cmdArgs = append(cmdArgs, "--max-memory", "2560")
@@ -19,7 +30,7 @@ func RunOpengrep(workDirectory string, binary string, files []string, outputFile
 	cmdArgs = append(cmdArgs, "--disable-version-check")
 
 	// Add output format if specified
-	if outputFormat == "sarif" {
+	if req.OutputFormat == "sarif" {
 		cmdArgs = append(cmdArgs, "--sarif")
 	}
 
@@ -35,38 +46,38 @@ func RunOpengrep(workDirectory string, binary string, files []string, outputFile
 	}
 
 	// Add files to analyze - if no files specified, analyze current directory
-	if len(files) > 0 {
-		cmdArgs = append(cmdArgs, files...)
+	if len(req.PathsToCheck) > 0 {
+		cmdArgs = append(cmdArgs, req.PathsToCheck...)
 	} else {
 		cmdArgs = append(cmdArgs, ".")
 	}
 
 	// Create Opengrep command
-	cmd := exec.Command(binary, cmdArgs...)
-	cmd.Dir = workDirectory
+	cmd := NewToolCommand(ctx, req.Tool.Binaries["opengrep"], cmdArgs...)
+	cmd.Dir = req.WorkDirectory
 
-	if outputFile != "" {
+	if req.OutputFile != "" {
 		// If output file is specified, create it and redirect output
 		var outputWriter *os.File
 		var err error
-		outputWriter, err = os.Create(filepath.Clean(outputFile))
+		outputWriter, err = os.Create(filepath.Clean(req.OutputFile))
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
 		defer outputWriter.Close()
 		cmd.Stdout = outputWriter
 	} else {
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = req.Stdout
 	}
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = req.Stderr
 
 	// Run Opengrep
 	if err := cmd.Run(); err != nil {
 		// Opengrep returns non-zero exit code when it finds issues, which is expected
 		if _, ok := err.(*exec.ExitError); !ok {
-			return fmt.Errorf("failed to run opengrep: %w", err)
+			return nil, fmt.Errorf("failed to run opengrep: %w", err)
 		}
 	}
 
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
@@ -2,14 +2,23 @@ package tools
 
 import (
 	"codacy/cli-v2/config"
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
 )
 
-// RunTrivy executes Trivy vulnerability scanner with the specified options
-func RunTrivy(repositoryToAnalyseDirectory string, trivyBinary string, pathsToCheck []string, outputFile string, outputFormat string) error {
-	cmd := exec.Command(trivyBinary, "fs", "--detection-priority", "comprehensive")
+type trivyRunner struct{}
+
+func init() {
+	RegisterRunner(trivyRunner{})
+}
+
+func (trivyRunner) Name() string {
+	return "trivy"
+}
+
+// Run executes Trivy vulnerability scanner with the specified options
+func (r trivyRunner) Run(ctx context.Context, req *RunRequest) (*RunResult, error) {
+	cmd := NewToolCommand(ctx, req.Tool.Binaries["trivy"], "fs", "--detection-priority", "comprehensive")
 
 	// Add config file from tools-configs directory if it exists
 	if configFile, exists := ConfigFileExists(config.Config, "trivy.yaml"); exists {
@@ -17,28 +26,28 @@ func RunTrivy(repositoryToAnalyseDirectory string, trivyBinary string, pathsToCh
 	}
 
 	// Add format options
-	if outputFile != "" {
-		cmd.Args = append(cmd.Args, "--output", outputFile)
+	if req.OutputFile != "" {
+		cmd.Args = append(cmd.Args, "--output", req.OutputFile)
 	}
 
-	if outputFormat == "sarif" {
+	if req.OutputFormat == "sarif" {
 		cmd.Args = append(cmd.Args, "--format", "sarif")
 	}
 
 	// Add specific targets or use current directory
-	if len(pathsToCheck) > 0 {
-		cmd.Args = append(cmd.Args, pathsToCheck...)
+	if len(req.PathsToCheck) > 0 {
+		cmd.Args = append(cmd.Args, req.PathsToCheck...)
 	} else {
 		cmd.Args = append(cmd.Args, ".")
 	}
 
-	cmd.Dir = repositoryToAnalyseDirectory
-	cmd.Stderr = os.Stderr
-	cmd.Stdout = os.Stdout
+	cmd.Dir = req.WorkDirectory
+	cmd.Stderr = req.Stderr
+	cmd.Stdout = req.Stdout
 
 	err := cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to run Trivy: %w", err)
+		return nil, fmt.Errorf("failed to run Trivy: %w", err)
 	}
-	return nil
+	return NewRunResult(r.Name(), req, cmd), nil
 }
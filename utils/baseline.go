@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"codacy/cli-v2/constants"
+	"codacy/cli-v2/domain"
+)
+
+// baselineVersion is the version of the baseline file format
+const baselineVersion = 1
+
+// Baseline holds the fingerprints of the pre-existing issues that should not be reported
+type Baseline struct {
+	Version int `json:"version"`
+	// CreatedAt is the time the baseline was written
+	CreatedAt time.Time `json:"createdAt"`
+	// SuppressedIssues is the total number of issues the baseline suppresses
+	SuppressedIssues int             `json:"suppressedIssues"`
+	Issues           []BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue is a fingerprinted issue of the baseline. Count is the number of identical issues,
+// e.g. the same rule violated on two identical lines of a file.
+type BaselineIssue struct {
+	Fingerprint string `json:"fingerprint"`
+	Tool        string `json:"tool"`
+	RuleID      string `json:"ruleId"`
+	Path        string `json:"path"`
+	Count       int    `json:"count"`
+}
+
+// NewBaseline creates a baseline from all the results of a SARIF report, except the suppressed ones.
+// Paths are normalized relative to baseDir and the analyzed files are read from it to compute fingerprints.
+func NewBaseline(sarifData []byte, baseDir string) (*Baseline, error) {
+	toolResults, err := ParseSarifIssues(sarifData, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	issuesByFingerprint := make(map[string]*BaselineIssue)
+	total := 0
+	for _, results := range toolResults {
+		for _, issue := range results.Issues {
+			if issue.Suppressed {
+				continue
+			}
+			total++
+			if baselineIssue, ok := issuesByFingerprint[issue.Fingerprint]; ok {
+				baselineIssue.Count++
+				continue
+			}
+			issuesByFingerprint[issue.Fingerprint] = &BaselineIssue{
+				Fingerprint: issue.Fingerprint,
+				Tool:        issue.Tool,
+				RuleID:      issue.PatternID,
+				Path:        issue.Path,
+				Count:       1,
+			}
+		}
+	}
+
+	issues := make([]BaselineIssue, 0, len(issuesByFingerprint))
+	for _, issue := range issuesByFingerprint {
+		issues = append(issues, *issue)
+	}
+	// Keep the file stable between runs so it produces readable diffs
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		if issues[i].Tool != issues[j].Tool {
+			return issues[i].Tool < issues[j].Tool
+		}
+		if issues[i].RuleID != issues[j].RuleID {
+			return issues[i].RuleID < issues[j].RuleID
+		}
+		return issues[i].Fingerprint < issues[j].Fingerprint
+	})
+
+	return &Baseline{
+		Version:          baselineVersion,
+		CreatedAt:        time.Now().UTC(),
+		SuppressedIssues: total,
+		Issues:           issues,
+	}, nil
+}
+
+// LoadBaseline reads a baseline file. It returns an error satisfying os.IsNotExist when the file doesn't exist.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+	}
+	if baseline.Version > baselineVersion {
+		return nil, fmt.Errorf("baseline file %s has version %d, which is not supported by this CLI version", path, baseline.Version)
+	}
+	return &baseline, nil
+}
+
+// Save writes the baseline to the given path
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), constants.DefaultFilePerms); err != nil {
+		return fmt.Errorf("failed to write baseline file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Apply removes from a SARIF report the results that are part of the baseline.
+// Each baseline issue suppresses at most Count results, so new occurrences of an existing issue are still reported.
+// It returns the filtered SARIF and the number of suppressed results.
+func (b *Baseline) Apply(sarifData []byte, baseDir string) ([]byte, int, error) {
+	remaining := make(map[string]int, len(b.Issues))
+	for _, issue := range b.Issues {
+		remaining[issue.Fingerprint] += issue.Count
+	}
+
+	return FilterSarifResults(sarifData, baseDir, func(issue domain.Issue) bool {
+		if issue.Suppressed {
+			return true
+		}
+		if remaining[issue.Fingerprint] > 0 {
+			remaining[issue.Fingerprint]--
+			return false
+		}
+		return true
+	})
+}
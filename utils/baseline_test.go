@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sarifWithResults(t *testing.T, toolName string, results ...Result) []byte {
+	t.Helper()
+	report := SarifReport{
+		Version: "2.1.0",
+		Runs:    []Run{{Tool: Tool{Driver: Driver{Name: toolName}}, Results: results}},
+	}
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	return data
+}
+
+func resultAt(ruleID string, uri string, line int) Result {
+	return Result{
+		RuleID:  ruleID,
+		Message: MessageText{Text: ruleID},
+		Locations: []Location{{PhysicalLocation: PhysicalLocation{
+			ArtifactLocation: ArtifactLocation{URI: uri},
+			Region:           Region{StartLine: line},
+		}}},
+	}
+}
+
+func TestBaselineSuppressesExistingIssuesAfterLineShift(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "app.js")
+	require.NoError(t, os.WriteFile(filePath, []byte("function f() {\n  var a = 1\n}\n"), 0644))
+
+	baseline, err := NewBaseline(sarifWithResults(t, "ESLint",
+		resultAt("no-var", "app.js", 2),
+		resultAt("func-style", "app.js", 1),
+	), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, baseline.SuppressedIssues)
+	assert.Len(t, baseline.Issues, 2)
+
+	// Lines are inserted above the existing issue and a new issue is introduced below it
+	require.NoError(t, os.WriteFile(filePath, []byte("// header\n\nfunction f() {\n  var a = 1\n}\n\nvar c = 3\n"), 0644))
+	filtered, suppressed, err := baseline.Apply(sarifWithResults(t, "ESLint",
+		resultAt("no-var", "app.js", 4),
+		resultAt("no-var", "app.js", 7),
+	), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, suppressed)
+
+	var report SarifReport
+	require.NoError(t, json.Unmarshal(filtered, &report))
+	require.Len(t, report.Runs[0].Results, 1)
+	assert.Equal(t, 7, report.Runs[0].Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestBaselineCountsIdenticalIssues(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.py"), []byte("x\nx\nx\nx\nx\nx\nx\n"), 0644))
+
+	baseline, err := NewBaseline(sarifWithResults(t, "Pylint",
+		resultAt("dup", "a.py", 3),
+		resultAt("dup", "a.py", 4),
+	), dir)
+	require.NoError(t, err)
+	require.Len(t, baseline.Issues, 1)
+	assert.Equal(t, 2, baseline.Issues[0].Count)
+
+	_, suppressed, err := baseline.Apply(sarifWithResults(t, "Pylint",
+		resultAt("dup", "a.py", 3),
+		resultAt("dup", "a.py", 4),
+		resultAt("dup", "a.py", 5),
+	), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, suppressed, "only as many occurrences as recorded should be suppressed")
+}
+
+func TestBaselineSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	baseline := &Baseline{
+		Version:          baselineVersion,
+		SuppressedIssues: 1,
+		Issues:           []BaselineIssue{{Fingerprint: "abc", Tool: "ESLint", RuleID: "semi", Path: "app.js", Count: 1}},
+	}
+	require.NoError(t, baseline.Save(path))
+
+	loaded, err := LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline.Issues, loaded.Issues)
+	assert.Equal(t, 1, loaded.SuppressedIssues)
+
+	_, err = LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"codacy/cli-v2/domain"
+)
+
+// CodacyFingerprintKey is the key of the fingerprint computed by ComputeFingerprint in the partialFingerprints of SARIF results
+const CodacyFingerprintKey = "codacyFingerprint/v1"
+
+// fingerprintContextLines is the number of lines before and after the flagged line that are part of a fingerprint
+const fingerprintContextLines = 1
+
+// SourceFiles reads and caches the lines of the analyzed files, so fingerprinting many results
+// of the same file only reads it once
+type SourceFiles struct {
+	baseDir string
+	lines   map[string][]string
+}
+
+// NewSourceFiles creates a SourceFiles that resolves relative paths against baseDir
+func NewSourceFiles(baseDir string) *SourceFiles {
+	return &SourceFiles{baseDir: baseDir, lines: make(map[string][]string)}
+}
+
+// Lines returns the lines of the file at the given slash separated relative path.
+// Files that cannot be read have no lines.
+func (s *SourceFiles) Lines(path string) []string {
+	if lines, ok := s.lines[path]; ok {
+		return lines
+	}
+
+	var lines []string
+	if file, err := os.Open(filepath.Join(s.baseDir, filepath.FromSlash(path))); err == nil {
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		file.Close()
+	}
+
+	s.lines[path] = lines
+	return lines
+}
+
+// ComputeFingerprint returns a stable fingerprint for an issue. It is computed from the tool, the rule,
+// the slash separated relative path and a whitespace insensitive hash of the flagged line and its context,
+// so it does not change when lines are added or removed elsewhere in the file.
+func ComputeFingerprint(toolName string, ruleID string, path string, lines []string, startLine int) string {
+	hash := sha256.New()
+	for _, part := range []string{strings.ToLower(toolName), ruleID, path} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	if startLine > 0 && startLine <= len(lines) {
+		first := max(startLine-fingerprintContextLines, 1)
+		last := min(startLine+fingerprintContextLines, len(lines))
+		for line := first; line <= last; line++ {
+			hash.Write([]byte(removeWhitespace(lines[line-1])))
+			hash.Write([]byte{'\n'})
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// AddSarifFingerprints adds the fingerprint of every result to its partialFingerprints, so results can be tracked
+// across commits by code scanning services. Fingerprints supplied by the tools are kept.
+// Paths are normalized relative to baseDir and the analyzed files are read from it to compute fingerprints.
+func AddSarifFingerprints(sarifData []byte, baseDir string) ([]byte, error) {
+	return editSarifResults(sarifData, NewSourceFiles(baseDir), func(issue domain.Issue, result map[string]interface{}) bool {
+		partialFingerprints, ok := result["partialFingerprints"].(map[string]interface{})
+		if !ok {
+			partialFingerprints = make(map[string]interface{})
+			result["partialFingerprints"] = partialFingerprints
+		}
+		partialFingerprints[CodacyFingerprintKey] = issue.Fingerprint
+		return true
+	})
+}
+
+// removeWhitespace strips every whitespace character from a line
+func removeWhitespace(line string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, line)
+}
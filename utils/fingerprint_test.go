@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeFingerprintSurvivesLineShifts(t *testing.T) {
+	original := []string{"func a() {", "  x := 1", "  return x", "}"}
+	shifted := []string{"// new comment", "", "func a() {", "  x := 1", "  return x", "}"}
+
+	assert.Equal(t,
+		ComputeFingerprint("Revive", "unused", "main.go", original, 2),
+		ComputeFingerprint("Revive", "unused", "main.go", shifted, 4))
+}
+
+func TestComputeFingerprintIgnoresWhitespace(t *testing.T) {
+	original := []string{"if (a) {", "  foo(a, b);", "}"}
+	reformatted := []string{"if (a) {", "\tfoo(a,b);  ", "}"}
+
+	assert.Equal(t,
+		ComputeFingerprint("ESLint", "no-undef", "app.js", original, 2),
+		ComputeFingerprint("ESLint", "no-undef", "app.js", reformatted, 2))
+}
+
+func TestComputeFingerprintDiffers(t *testing.T) {
+	lines := []string{"a", "b", "c", "d", "e"}
+	base := ComputeFingerprint("ESLint", "no-undef", "app.js", lines, 2)
+
+	assert.NotEqual(t, base, ComputeFingerprint("Pylint", "no-undef", "app.js", lines, 2), "tool")
+	assert.NotEqual(t, base, ComputeFingerprint("ESLint", "semi", "app.js", lines, 2), "rule")
+	assert.NotEqual(t, base, ComputeFingerprint("ESLint", "no-undef", "other.js", lines, 2), "path")
+	assert.NotEqual(t, base, ComputeFingerprint("ESLint", "no-undef", "app.js", lines, 4), "content")
+	assert.Equal(t, base, ComputeFingerprint("eslint", "no-undef", "app.js", lines, 2), "tool name case")
+}
+
+func TestAddSarifFingerprints(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "app.js"), []byte("const a = 1\nconst b = 2\n"), 0644))
+	sarif := `{
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {"name": "Trivy"}},
+			"results": [
+				{"ruleId": "a", "partialFingerprints": {"primaryLocationLineHash": "tool"},
+				 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "app.js"}, "region": {"startLine": 2}}}]},
+				{"ruleId": "b"}
+			]
+		}]
+	}`
+
+	fingerprinted, err := AddSarifFingerprints([]byte(sarif), baseDir)
+	require.NoError(t, err)
+
+	var report struct {
+		Runs []struct {
+			Results []struct {
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(fingerprinted, &report))
+	results := report.Runs[0].Results
+	assert.Equal(t, map[string]string{
+		"primaryLocationLineHash": "tool",
+		CodacyFingerprintKey:      ComputeFingerprint("Trivy", "a", "app.js", []string{"const a = 1", "const b = 2"}, 2),
+	}, results[0].PartialFingerprints)
+	assert.Equal(t, map[string]string{CodacyFingerprintKey: ComputeFingerprint("Trivy", "b", "", nil, 0)}, results[1].PartialFingerprints)
+
+	// The fingerprints of the SARIF are reused once the analyzed files changed
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "app.js"), []byte("changed\n"), 0644))
+	toolResults, err := ParseSarifIssues(fingerprinted, baseDir)
+	require.NoError(t, err)
+	assert.Equal(t, results[0].PartialFingerprints[CodacyFingerprintKey], toolResults[0].Issues[0].Fingerprint)
+}
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LineRange represents an inclusive range of lines in a file
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// ChangedLines maps each changed file, as a slash separated path relative to the analyzed directory,
+// to the ranges of lines that were added or modified in it
+type ChangedLines map[string][]LineRange
+
+// wholeFile is the range used for files that are entirely new, e.g. untracked files
+var wholeFile = LineRange{Start: 1, End: math.MaxInt32}
+
+// Files returns the sorted list of changed files
+func (c ChangedLines) Files() []string {
+	files := make([]string, 0, len(c))
+	for file := range c {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ContainsFile checks if the given slash separated relative path was changed
+func (c ChangedLines) ContainsFile(path string) bool {
+	_, ok := c[path]
+	return ok
+}
+
+// ContainsLine checks if the given line of a file was added or modified
+func (c ChangedLines) ContainsLine(path string, line int) bool {
+	for _, lineRange := range c[path] {
+		if line >= lineRange.Start && line <= lineRange.End {
+			return true
+		}
+	}
+	return false
+}
+
+// GetChangedLines returns the files and lines changed in the working tree of the git repository at
+// directory when compared to the merge base between ref and HEAD. Untracked files are considered
+// entirely changed. Deleted files are not reported.
+func GetChangedLines(directory string, ref string) (ChangedLines, error) {
+	base := ref
+	if mergeBase, err := runGit(directory, "merge-base", ref, "HEAD"); err == nil {
+		base = strings.TrimSpace(mergeBase)
+	}
+
+	diff, err := runGit(directory, "-c", "core.quotePath=false", "diff", "--unified=0", "--no-color", "--no-ext-diff", "--diff-filter=d", "--relative", base, "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute changes since %s: %w", ref, err)
+	}
+	changed := parseUnifiedDiff(diff)
+
+	untracked, err := runGit(directory, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for _, file := range strings.Split(untracked, "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			changed[file] = []LineRange{wholeFile}
+		}
+	}
+
+	return changed, nil
+}
+
+// runGit runs a git command in the given directory and returns its standard output
+func runGit(directory string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = directory
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// parseUnifiedDiff extracts the added or modified line ranges of each file from a diff
+// generated with --unified=0
+func parseUnifiedDiff(diff string) ChangedLines {
+	changed := make(ChangedLines)
+	currentFile := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			currentFile = parseDiffFileName(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@ ") && currentFile != "":
+			if lineRange, ok := parseHunkHeader(line); ok {
+				changed[currentFile] = append(changed[currentFile], lineRange)
+			}
+		}
+	}
+
+	return changed
+}
+
+// parseDiffFileName returns the path of the new version of a file in a diff header,
+// or an empty string when the file was deleted
+func parseDiffFileName(name string) string {
+	name = strings.TrimSpace(name)
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		name = unquoted
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(name, "b/")
+}
+
+// parseHunkHeader parses a hunk header like "@@ -10,2 +12,3 @@" and returns the added lines range.
+// It returns false for hunks that only remove lines.
+func parseHunkHeader(header string) (LineRange, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return LineRange{}, false
+	}
+
+	startAndCount := strings.SplitN(strings.TrimPrefix(fields[2], "+"), ",", 2)
+	start, err := strconv.Atoi(startAndCount[0])
+	if err != nil {
+		return LineRange{}, false
+	}
+
+	count := 1
+	if len(startAndCount) == 2 {
+		count, err = strconv.Atoi(startAndCount[1])
+		if err != nil {
+			return LineRange{}, false
+		}
+	}
+
+	if count == 0 {
+		return LineRange{}, false
+	}
+
+	return LineRange{Start: start, End: start + count - 1}, true
+}
+
+// ListRepositoryFiles returns the tracked and untracked, but not ignored, files of the git repository
+// at directory, as slash separated paths relative to directory. Files deleted from the working tree are not reported.
+func ListRepositoryFiles(directory string) ([]string, error) {
+	output, err := runGit(directory, "-c", "core.quotePath=false", "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository files: %w", err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, file := range strings.Split(output, "\x00") {
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		if info, err := os.Stat(filepath.Join(directory, filepath.FromSlash(file))); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// RepositoryPrefix returns the slash separated path of directory relative to the root of its git repository,
+// ending with a slash, or an empty string for the root itself
+func RepositoryPrefix(directory string) (string, error) {
+	output, err := runGit(directory, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the repository root: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := `diff --git a/src/app.js b/src/app.js
+index 1111111..2222222 100644
+--- a/src/app.js
++++ b/src/app.js
+@@ -3,0 +4,2 @@ function main() {
++  const a = 1;
++  const b = 2;
+@@ -10 +12 @@ function other() {
+-  return 1;
++  return 2;
+@@ -20,3 +21,0 @@ function removed() {
+diff --git a/old.py b/old.py
+deleted file mode 100644
+--- a/old.py
++++ /dev/null
+@@ -1,2 +0,0 @@
+diff --git a/new file.py b/new file.py
+new file mode 100644
+--- /dev/null
++++ b/new file.py
+@@ -0,0 +1,3 @@
+`
+
+	changed := parseUnifiedDiff(diff)
+
+	assert.Equal(t, []string{"new file.py", "src/app.js"}, changed.Files())
+	assert.Equal(t, []LineRange{{Start: 4, End: 5}, {Start: 12, End: 12}}, changed["src/app.js"])
+	assert.Equal(t, []LineRange{{Start: 1, End: 3}}, changed["new file.py"])
+	assert.False(t, changed.ContainsFile("old.py"))
+}
+
+func TestChangedLinesContainsLine(t *testing.T) {
+	changed := ChangedLines{
+		"src/app.js": {{Start: 4, End: 5}, {Start: 12, End: 12}},
+		"new.py":     {wholeFile},
+	}
+
+	assert.True(t, changed.ContainsLine("src/app.js", 4))
+	assert.True(t, changed.ContainsLine("src/app.js", 5))
+	assert.True(t, changed.ContainsLine("src/app.js", 12))
+	assert.False(t, changed.ContainsLine("src/app.js", 6))
+	assert.False(t, changed.ContainsLine("other.js", 4))
+	assert.True(t, changed.ContainsLine("new.py", 1000))
+}
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected LineRange
+		ok       bool
+	}{
+		{header: "@@ -1 +1 @@", expected: LineRange{Start: 1, End: 1}, ok: true},
+		{header: "@@ -5,2 +7,4 @@ func x()", expected: LineRange{Start: 7, End: 10}, ok: true},
+		{header: "@@ -5,2 +4,0 @@", ok: false},
+		{header: "@@ malformed", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			lineRange, ok := parseHunkHeader(tt.header)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, lineRange)
+			}
+		})
+	}
+}
+
+func TestGetChangedLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := runGit(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err)
+	}
+
+	git("init", "-q")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n}\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	git("branch", "base")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(1)\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.go"), []byte("package main\n"), 0644))
+
+	changed, err := GetChangedLines(dir, "base")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"main.go", "untracked.go"}, changed.Files())
+	assert.True(t, changed.ContainsLine("main.go", 4))
+	assert.False(t, changed.ContainsLine("main.go", 3))
+	assert.True(t, changed.ContainsLine("untracked.go", 1))
+}
+
+func TestListRepositoryFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		_, err := runGit(dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err)
+	}
+
+	git("init", "-q")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("ignored.js\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "tracked.js"), []byte("a\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deleted.js"), []byte("b\n"), 0644))
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "deleted.js")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.js"), []byte("c\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.js"), []byte("d\n"), 0644))
+
+	files, err := ListRepositoryFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{".gitignore", "src/tracked.js", "untracked.js"}, files)
+}
+
+func TestRepositoryPrefix(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	_, err := runGit(dir, "init", "-q")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "services", "api"), 0755))
+
+	prefix, err := RepositoryPrefix(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "", prefix)
+
+	prefix, err = RepositoryPrefix(filepath.Join(dir, "services", "api"))
+	require.NoError(t, err)
+	assert.Equal(t, "services/api/", prefix)
+}
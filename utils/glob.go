@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchPathGlob checks if a slash separated relative path matches a glob pattern. Besides the path.Match syntax,
+// a ** segment matches any number of directories, and a pattern without a slash matches the file name in any
+// directory, like in .gitignore files.
+func MatchPathGlob(pattern string, filePath string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	filePath = strings.TrimPrefix(filePath, "./")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// ** matches zero or more segments
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"src/app.js", "src/app.js", true},
+		{"src/*.js", "src/app.js", true},
+		{"src/*.js", "src/lib/app.js", false},
+		{"src/**/*.js", "src/app.js", true},
+		{"src/**/*.js", "src/lib/deep/app.js", true},
+		{"src/**", "src/lib/app.js", true},
+		{"**/test/**", "a/test/b.js", true},
+		{"*.js", "src/lib/app.js", true},
+		{"*.js", "src/lib/app.ts", false},
+		{"./src/*.js", "src/app.js", true},
+		{"lib/*.js", "src/lib/app.js", false},
+		{"[", "[", false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, MatchPathGlob(test.pattern, test.path), "%s with %s", test.pattern, test.path)
+	}
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"codacy/cli-v2/domain"
+)
+
+// sarifRunDocument is the part of a SARIF run needed to build issues
+type sarifRunDocument struct {
+	Tool struct {
+		Driver struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Artifacts []struct {
+		Location struct {
+			URI string `json:"uri"`
+		} `json:"location"`
+		Properties struct {
+			Metrics *domain.FileMetrics `json:"metrics"`
+		} `json:"properties"`
+	} `json:"artifacts"`
+	Results []sarifResultDocument `json:"results"`
+}
+
+// sarifResultDocument is the part of a SARIF result needed to build an issue
+type sarifResultDocument struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region sarifRegionDocument `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+	Fixes []struct {
+		Description struct {
+			Text string `json:"text"`
+		} `json:"description"`
+		ArtifactChanges []struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Replacements []struct {
+				DeletedRegion   sarifRegionDocument `json:"deletedRegion"`
+				InsertedContent struct {
+					Text string `json:"text"`
+				} `json:"insertedContent"`
+			} `json:"replacements"`
+		} `json:"artifactChanges"`
+	} `json:"fixes"`
+	Suppressions []struct {
+		Status string `json:"status"`
+	} `json:"suppressions"`
+	PartialFingerprints map[string]interface{} `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+}
+
+type sarifRegionDocument struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+func (r sarifRegionDocument) toRegion() domain.Region {
+	return domain.Region{StartLine: r.StartLine, StartColumn: r.StartColumn, EndLine: r.EndLine, EndColumn: r.EndColumn}
+}
+
+// ParseSarifIssues parses every run of a SARIF report into the issues of each tool.
+// Paths are made relative to baseDir, and the analyzed files are read from it to compute fingerprints.
+func ParseSarifIssues(sarifData []byte, baseDir string) ([]domain.ToolResults, error) {
+	var report struct {
+		Runs []sarifRunDocument `json:"runs"`
+	}
+	if err := json.Unmarshal(sarifData, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF data: %w", err)
+	}
+
+	sources := NewSourceFiles(baseDir)
+	toolResults := make([]domain.ToolResults, 0, len(report.Runs))
+	for _, run := range report.Runs {
+		results := domain.ToolResults{
+			Tool:    run.Tool.Driver.Name,
+			Version: run.Tool.Driver.Version,
+			Issues:  make([]domain.Issue, 0, len(run.Results)),
+		}
+		for _, artifact := range run.Artifacts {
+			if artifact.Location.URI == "" {
+				continue
+			}
+			path := NormalizeSarifURI(baseDir, artifact.Location.URI)
+			results.Files = append(results.Files, path)
+			if metrics := artifact.Properties.Metrics; metrics != nil {
+				metrics.Path = path
+				for _, block := range metrics.Duplication {
+					for i := range block.Locations {
+						block.Locations[i].Path = NormalizeSarifURI(baseDir, block.Locations[i].Path)
+					}
+				}
+				results.Metrics = append(results.Metrics, *metrics)
+			}
+		}
+		for _, result := range run.Results {
+			results.Issues = append(results.Issues, newIssue(results.Tool, results.Version, result, sources))
+		}
+		toolResults = append(toolResults, results)
+	}
+
+	return toolResults, nil
+}
+
+// newIssue builds the issue of a SARIF result, located at the first location of the result
+func newIssue(toolName string, toolVersion string, result sarifResultDocument, sources *SourceFiles) domain.Issue {
+	issue := domain.Issue{
+		Tool:        toolName,
+		ToolVersion: toolVersion,
+		PatternID:   result.RuleID,
+		Level:       result.Level,
+		Message:     result.Message.Text,
+	}
+	// Results without a level are warnings, as per the SARIF spec
+	if issue.Level == "" {
+		issue.Level = "warning"
+	}
+	codacy, _ := result.Properties[codacyPropertiesKey].(map[string]interface{})
+	if category, ok := result.Properties["category"].(string); ok {
+		issue.Category = category
+	} else {
+		issue.Category, _ = codacy["category"].(string)
+	}
+	issue.Severity, _ = codacy["severity"].(string)
+	// Suppressions under review or rejected don't suppress the result
+	for _, suppression := range result.Suppressions {
+		if suppression.Status == "" || suppression.Status == "accepted" {
+			issue.Suppressed = true
+		}
+	}
+
+	if len(result.Locations) > 0 {
+		location := result.Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != "" {
+			issue.Path = NormalizeSarifURI(sources.baseDir, location.ArtifactLocation.URI)
+		}
+		issue.Region = location.Region.toRegion()
+	}
+	for _, additional := range result.Locations[min(1, len(result.Locations)):] {
+		location := additional.PhysicalLocation
+		if location.ArtifactLocation.URI != "" {
+			issue.AdditionalLocations = append(issue.AdditionalLocations, domain.Location{
+				Path:   NormalizeSarifURI(sources.baseDir, location.ArtifactLocation.URI),
+				Region: location.Region.toRegion(),
+			})
+		}
+	}
+
+	if len(result.Fixes) > 0 {
+		fix := result.Fixes[0]
+		issue.Fix = &domain.Fix{Description: fix.Description.Text}
+		for _, change := range fix.ArtifactChanges {
+			path := issue.Path
+			if change.ArtifactLocation.URI != "" {
+				path = NormalizeSarifURI(sources.baseDir, change.ArtifactLocation.URI)
+			}
+			for _, replacement := range change.Replacements {
+				issue.Fix.Replacements = append(issue.Fix.Replacements, domain.Replacement{
+					Path:         path,
+					Region:       replacement.DeletedRegion.toRegion(),
+					InsertedText: replacement.InsertedContent.Text,
+				})
+			}
+		}
+	}
+
+	// Fingerprints added to the SARIF by AddSarifFingerprints are reused, so the analyzed files are not needed
+	if fingerprint, _ := result.PartialFingerprints[CodacyFingerprintKey].(string); fingerprint != "" {
+		issue.Fingerprint = fingerprint
+		return issue
+	}
+	var lines []string
+	if issue.Path != "" {
+		lines = sources.Lines(issue.Path)
+	}
+	issue.Fingerprint = ComputeFingerprint(toolName, issue.PatternID, issue.Path, lines, issue.Region.StartLine)
+
+	return issue
+}
+
+// issueFromGenericResult builds the issue of a SARIF result in its generic JSON form
+func issueFromGenericResult(toolName string, toolVersion string, result map[string]interface{}, sources *SourceFiles) (domain.Issue, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return domain.Issue{}, fmt.Errorf("failed to marshal SARIF result: %w", err)
+	}
+	var document sarifResultDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return domain.Issue{}, fmt.Errorf("failed to parse SARIF result: %w", err)
+	}
+	return newIssue(toolName, toolVersion, document, sources), nil
+}
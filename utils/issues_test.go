@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSarifIssues(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "app.js"), []byte("var a = 1\nvar b = 2\n"), 0644))
+
+	sarif := `{
+		"version": "2.1.0",
+		"runs": [
+			{
+				"tool": {"driver": {"name": "ESLint", "version": "8.57.0"}},
+				"artifacts": [{"location": {"uri": "file://` + filepath.ToSlash(dir) + `/src/app.js"}}],
+				"results": [
+					{
+						"ruleId": "no-var",
+						"level": "error",
+						"message": {"text": "Unexpected var"},
+						"locations": [{"physicalLocation": {
+							"artifactLocation": {"uri": "file://` + filepath.ToSlash(dir) + `/src/app.js"},
+							"region": {"startLine": 2, "startColumn": 1, "endLine": 2, "endColumn": 10}
+						}}],
+						"fixes": [{
+							"description": {"text": "Use let"},
+							"artifactChanges": [{
+								"artifactLocation": {"uri": "src/app.js"},
+								"replacements": [{"deletedRegion": {"startLine": 2, "startColumn": 1, "endColumn": 4}, "insertedContent": {"text": "let"}}]
+							}]
+						}],
+						"properties": {"category": "BestPractice"}
+					}
+				]
+			},
+			{
+				"tool": {"driver": {"name": "Trivy"}},
+				"results": [{
+					"ruleId": "CVE-1",
+					"message": {"text": "Vulnerable"},
+					"properties": {"codacy": {"category": "Security", "severity": "High"}}
+				}]
+			}
+		]
+	}`
+
+	toolResults, err := ParseSarifIssues([]byte(sarif), dir)
+	require.NoError(t, err)
+	require.Len(t, toolResults, 2)
+
+	eslint := toolResults[0]
+	assert.Equal(t, "ESLint", eslint.Tool)
+	assert.Equal(t, "8.57.0", eslint.Version)
+	assert.Equal(t, []string{"src/app.js"}, eslint.Files)
+	require.Len(t, eslint.Issues, 1)
+	assert.Equal(t, domain.Issue{
+		Tool:        "ESLint",
+		ToolVersion: "8.57.0",
+		PatternID:   "no-var",
+		Path:        "src/app.js",
+		Region:      domain.Region{StartLine: 2, StartColumn: 1, EndLine: 2, EndColumn: 10},
+		Level:       "error",
+		Category:    "BestPractice",
+		Message:     "Unexpected var",
+		Fix: &domain.Fix{
+			Description: "Use let",
+			Replacements: []domain.Replacement{
+				{Path: "src/app.js", Region: domain.Region{StartLine: 2, StartColumn: 1, EndColumn: 4}, InsertedText: "let"},
+			},
+		},
+		Fingerprint: ComputeFingerprint("ESLint", "no-var", "src/app.js", []string{"var a = 1", "var b = 2"}, 2),
+	}, eslint.Issues[0])
+
+	trivy := toolResults[1]
+	require.Len(t, trivy.Issues, 1)
+	assert.Equal(t, "warning", trivy.Issues[0].Level, "results without level are warnings")
+	assert.Empty(t, trivy.Issues[0].Path)
+	assert.Equal(t, "Security", trivy.Issues[0].Category)
+	assert.Equal(t, "High", trivy.Issues[0].Severity)
+}
+
+func TestParseSarifIssuesKeepsAdditionalLocations(t *testing.T) {
+	sarif := `{
+		"runs": [{
+			"tool": {"driver": {"name": "Opengrep"}},
+			"results": [{
+				"ruleId": "taint",
+				"message": {"text": "Tainted data"},
+				"locations": [
+					{"physicalLocation": {"artifactLocation": {"uri": "src/a.py"}, "region": {"startLine": 3}}},
+					{"physicalLocation": {"artifactLocation": {"uri": "./src/b.py"}, "region": {"startLine": 7}}},
+					{"physicalLocation": {"region": {"startLine": 9}}}
+				]
+			}]
+		}]
+	}`
+
+	toolResults, err := ParseSarifIssues([]byte(sarif), t.TempDir())
+	require.NoError(t, err)
+
+	issue := toolResults[0].Issues[0]
+	assert.Equal(t, "src/a.py", issue.Path)
+	assert.Equal(t, []domain.Location{{Path: "src/b.py", Region: domain.Region{StartLine: 7}}}, issue.AdditionalLocations)
+	assert.Equal(t, []domain.Location{
+		{Path: "src/a.py", Region: domain.Region{StartLine: 3}},
+		{Path: "src/b.py", Region: domain.Region{StartLine: 7}},
+	}, issue.Locations())
+}
+
+func TestParseSarifIssuesReadsFileMetrics(t *testing.T) {
+	sarif := `{
+		"runs": [{
+			"tool": {"driver": {"name": "Lizard"}},
+			"artifacts": [
+				{"location": {"uri": "./src/app.py"}, "properties": {"metrics": {"nloc": 12, "ccn": 5, "maxCcn": 3, "functions": 2,
+					"lineComplexities": [{"line": 1, "value": 3}, {"line": 8, "value": 2}],
+					"duplication": [{"lines": "x = 1", "locations": [{"path": "./src/app.py", "startLine": 1, "endLine": 1}, {"path": "src/lib.py", "startLine": 4, "endLine": 4}]}]}}},
+				{"location": {"uri": "src/empty.py"}}
+			],
+			"results": []
+		}]
+	}`
+
+	toolResults, err := ParseSarifIssues([]byte(sarif), t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, toolResults, 1)
+
+	assert.Equal(t, []string{"src/app.py", "src/empty.py"}, toolResults[0].Files)
+	assert.Equal(t, []domain.FileMetrics{{
+		Path:             "src/app.py",
+		Nloc:             12,
+		Ccn:              5,
+		MaxCcn:           3,
+		Functions:        2,
+		LineComplexities: []domain.LineComplexity{{Line: 1, Value: 3}, {Line: 8, Value: 2}},
+		Duplication: []domain.DuplicatedBlock{{Lines: "x = 1", Locations: []domain.DuplicatedLocation{
+			{Path: "src/app.py", StartLine: 1, EndLine: 1},
+			{Path: "src/lib.py", StartLine: 4, EndLine: 4},
+		}}},
+	}}, toolResults[0].Metrics)
+}
+
+func TestFilterSarifResultsKeepsUnknownFields(t *testing.T) {
+	sarif := `{
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {"name": "PMD", "rules": [{"id": "a"}]}},
+			"results": [
+				{"ruleId": "a", "level": "error", "properties": {"custom": 1}},
+				{"ruleId": "b", "level": "note"}
+			]
+		}]
+	}`
+
+	filtered, removed, err := FilterSarifResults([]byte(sarif), t.TempDir(), func(issue domain.Issue) bool {
+		return issue.Level == "error"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Contains(t, string(filtered), `"custom": 1`)
+	assert.Contains(t, string(filtered), `"rules"`)
+	assert.NotContains(t, string(filtered), `"ruleId": "b"`)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+)
+
+// RelativePath converts a SARIF artifact URI, either a file:// URI or a path, into a path relative to baseDir.
+// URIs that are already relative, or that can't be made relative, are returned as they are.
+func RelativePath(baseDir string, fullURI string) string {
+
+	localPath := fullURI
+	u, err := url.Parse(fullURI)
+	if err == nil && u.Scheme == "file" {
+		// url.Path extracts the local path component correctly
+		localPath = u.Path
+	}
+	if !filepath.IsAbs(localPath) {
+		return localPath
+	}
+	relativePath, err := filepath.Rel(baseDir, localPath)
+	if err != nil {
+		// Fallback to the normalized absolute path if calculation fails
+		fmt.Printf("Warning: Could not get relative path for '%s' relative to '%s': %v. Using absolute path.\n", localPath, baseDir, err)
+		return localPath
+	}
+
+	return relativePath
+}
+
+// NormalizeSarifURI converts a SARIF artifact URI, either a file:// URI, an absolute path or a relative path,
+// into a clean slash separated path relative to baseDir
+func NormalizeSarifURI(baseDir string, uri string) string {
+	return filepath.ToSlash(filepath.Clean(RelativePath(baseDir, uri)))
+}
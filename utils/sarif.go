@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"codacy/cli-v2/domain"
 )
 
 // PylintIssue represents a single issue in Pylint's JSON output
@@ -27,8 +29,19 @@ type SarifReport struct {
 }
 
 type Run struct {
-	Tool    Tool     `json:"tool"`
-	Results []Result `json:"results"`
+	Tool      Tool       `json:"tool"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	Results   []Result   `json:"results"`
+}
+
+// Artifact is a file analyzed by a tool
+type Artifact struct {
+	Location   ArtifactLocation    `json:"location"`
+	Properties *ArtifactProperties `json:"properties,omitempty"`
+}
+
+type ArtifactProperties struct {
+	Metrics *domain.FileMetrics `json:"metrics,omitempty"`
 }
 
 type Tool struct {
@@ -276,3 +289,106 @@ func FilterRulesFromSarif(sarifData []byte) ([]byte, error) {
 
 	return filteredData, nil
 }
+
+// FilterSarifResults removes from every run the results whose issue is not kept by keep.
+// Results are filtered in their generic JSON form so no field is lost when marshaling back.
+// Issue paths are made relative to baseDir. It returns the filtered SARIF and the number of removed results.
+func FilterSarifResults(sarifData []byte, baseDir string, keep func(issue domain.Issue) bool) ([]byte, int, error) {
+	removed := 0
+	filteredData, err := editSarifResults(sarifData, NewSourceFiles(baseDir), func(issue domain.Issue, result map[string]interface{}) bool {
+		if !keep(issue) {
+			removed++
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return filteredData, removed, nil
+}
+
+// FilterSarifRuns removes the runs whose tool, given by its SARIF driver name, is not kept by keep.
+// It returns the filtered SARIF and the number of removed runs.
+func FilterSarifRuns(sarifData []byte, keep func(driverName string) bool) ([]byte, int, error) {
+	var report map[string]interface{}
+	if err := json.Unmarshal(sarifData, &report); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse SARIF data: %w", err)
+	}
+
+	removed := 0
+	if runs, ok := report["runs"].([]interface{}); ok {
+		kept := make([]interface{}, 0, len(runs))
+		for _, run := range runs {
+			runMap, ok := run.(map[string]interface{})
+			if ok {
+				if driverName, _ := runDriver(runMap); !keep(driverName) {
+					removed++
+					continue
+				}
+			}
+			kept = append(kept, run)
+		}
+		report["runs"] = kept
+	}
+
+	filteredData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return filteredData, removed, nil
+}
+
+// editSarifResults calls edit with every result of every run, in its generic JSON form, and the issue built from it.
+// edit may change the result in place, and results for which it returns false are removed.
+func editSarifResults(sarifData []byte, sources *SourceFiles, edit func(issue domain.Issue, result map[string]interface{}) bool) ([]byte, error) {
+	var report map[string]interface{}
+	if err := json.Unmarshal(sarifData, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF data: %w", err)
+	}
+
+	if runs, ok := report["runs"].([]interface{}); ok {
+		for _, run := range runs {
+			runMap, ok := run.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			results, ok := runMap["results"].([]interface{})
+			if !ok {
+				continue
+			}
+			toolName, toolVersion := runDriver(runMap)
+			kept := make([]interface{}, 0, len(results))
+			for _, result := range results {
+				resultMap, ok := result.(map[string]interface{})
+				if !ok {
+					kept = append(kept, result)
+					continue
+				}
+				issue, err := issueFromGenericResult(toolName, toolVersion, resultMap, sources)
+				if err != nil {
+					return nil, err
+				}
+				if edit(issue, resultMap) {
+					kept = append(kept, result)
+				}
+			}
+			runMap["results"] = kept
+		}
+	}
+
+	editedData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return editedData, nil
+}
+
+// runDriver returns the driver name and version of a generic SARIF run
+func runDriver(run map[string]interface{}) (string, string) {
+	tool, _ := run["tool"].(map[string]interface{})
+	driver, _ := tool["driver"].(map[string]interface{})
+	name, _ := driver["name"].(string)
+	version, _ := driver["version"].(string)
+	return name, version
+}
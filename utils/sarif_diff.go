@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"reflect"
+
+	"codacy/cli-v2/domain"
+)
+
+// IssuesDiff is the comparison of the issues of two analyses
+type IssuesDiff struct {
+	// New are the issues of the head analysis that are not in the base analysis
+	New []domain.Issue `json:"new"`
+	// Fixed are the issues of the base analysis that are not in the head analysis anymore
+	Fixed []domain.Issue `json:"fixed"`
+	// Unchanged are the issues of the head analysis that were already in the base analysis
+	Unchanged []domain.Issue `json:"unchanged"`
+}
+
+// DiffIssues compares the issues of two analyses by fingerprint, so issues are matched even if their lines moved.
+// Identical issues are matched one to one, so a new occurrence of an existing issue is new. Suppressed issues are ignored.
+func DiffIssues(base []domain.ToolResults, head []domain.ToolResults) IssuesDiff {
+	remaining := make(map[string][]domain.Issue)
+	for _, results := range base {
+		for _, issue := range results.Issues {
+			if !issue.Suppressed {
+				remaining[issue.Fingerprint] = append(remaining[issue.Fingerprint], issue)
+			}
+		}
+	}
+
+	var diff IssuesDiff
+	for _, results := range head {
+		for _, issue := range results.Issues {
+			if issue.Suppressed {
+				continue
+			}
+			if matches := remaining[issue.Fingerprint]; len(matches) > 0 {
+				remaining[issue.Fingerprint] = matches[1:]
+				diff.Unchanged = append(diff.Unchanged, issue)
+				continue
+			}
+			diff.New = append(diff.New, issue)
+		}
+	}
+
+	// Report the fixed issues in the order of the base analysis
+	for _, results := range base {
+		for _, issue := range results.Issues {
+			if issue.Suppressed {
+				continue
+			}
+			if matches := remaining[issue.Fingerprint]; len(matches) > 0 && reflect.DeepEqual(matches[0], issue) {
+				remaining[issue.Fingerprint] = matches[1:]
+				diff.Fixed = append(diff.Fixed, issue)
+			}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffIssues(t *testing.T) {
+	issue := func(fingerprint string, line int) domain.Issue {
+		return domain.Issue{Tool: "ESLint", PatternID: "semi", Path: "a.js", Region: domain.Region{StartLine: line}, Fingerprint: fingerprint}
+	}
+
+	base := []domain.ToolResults{{Tool: "ESLint", Issues: []domain.Issue{
+		issue("kept", 1),
+		issue("fixed", 2),
+		issue("duplicated", 3),
+		issue("duplicated", 4),
+		issue("duplicated", 5),
+	}}}
+	suppressed := issue("suppressed", 9)
+	suppressed.Suppressed = true
+	head := []domain.ToolResults{{Tool: "ESLint", Issues: []domain.Issue{
+		issue("kept", 11),
+		issue("duplicated", 13),
+		issue("new", 20),
+		suppressed,
+	}}}
+
+	diff := DiffIssues(base, head)
+
+	assert.Equal(t, []domain.Issue{issue("new", 20)}, diff.New)
+	assert.Equal(t, []domain.Issue{issue("kept", 11), issue("duplicated", 13)}, diff.Unchanged)
+	assert.Equal(t, []domain.Issue{issue("fixed", 2), issue("duplicated", 4), issue("duplicated", 5)}, diff.Fixed)
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"codacy/cli-v2/domain"
+)
+
+// codacyPropertiesKey is the key of the Codacy metadata in the property bags of SARIF rules and results
+const codacyPropertiesKey = "codacy"
+
+// PatternLookup returns the Codacy pattern of a rule reported by the tool of a SARIF run, if known
+type PatternLookup func(driverName string, driverVersion string, ruleID string) (domain.PatternDefinition, bool)
+
+// sarifLevelsByCodacyLevel maps the levels of Codacy patterns to SARIF levels
+var sarifLevelsByCodacyLevel = map[string]string{
+	"error":   "error",
+	"warning": "warning",
+	"info":    "note",
+}
+
+// EnrichSarifRules completes the rules of every run with the metadata of their Codacy pattern: title, description,
+// level, category, severity and time to fix. Rules missing from a run are added for the reported rules with a known
+// pattern, and results get the category and severity of their pattern in properties.codacy.
+// The metadata reported by the tools is kept; Codacy only fills in what is missing.
+func EnrichSarifRules(sarifData []byte, lookup PatternLookup) ([]byte, error) {
+	var report map[string]interface{}
+	if err := json.Unmarshal(sarifData, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF data: %w", err)
+	}
+
+	runs, _ := report["runs"].([]interface{})
+	for _, run := range runs {
+		runMap, ok := run.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tool, _ := runMap["tool"].(map[string]interface{})
+		driver, _ := tool["driver"].(map[string]interface{})
+		if driver == nil {
+			continue
+		}
+		driverName, _ := driver["name"].(string)
+		driverVersion, _ := driver["version"].(string)
+
+		rules, _ := driver["rules"].([]interface{})
+		rulesByID := make(map[string]map[string]interface{}, len(rules))
+		for _, rule := range rules {
+			if ruleMap, ok := rule.(map[string]interface{}); ok {
+				if id, ok := ruleMap["id"].(string); ok {
+					rulesByID[id] = ruleMap
+				}
+			}
+		}
+
+		results, _ := runMap["results"].([]interface{})
+		for _, result := range results {
+			resultMap, ok := result.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ruleID, _ := resultMap["ruleId"].(string)
+			if ruleID == "" {
+				continue
+			}
+			pattern, ok := lookup(driverName, driverVersion, ruleID)
+			if !ok {
+				continue
+			}
+
+			setCodacyProperties(resultMap, map[string]interface{}{
+				"category": pattern.Category,
+				"severity": patternSeverity(pattern),
+			})
+			if _, ok := rulesByID[ruleID]; !ok {
+				rule := map[string]interface{}{"id": ruleID}
+				rulesByID[ruleID] = rule
+				rules = append(rules, rule)
+			}
+		}
+
+		for id, rule := range rulesByID {
+			if pattern, ok := lookup(driverName, driverVersion, id); ok {
+				enrichRule(rule, pattern)
+			}
+		}
+		if rules != nil {
+			driver["rules"] = rules
+		}
+	}
+
+	enrichedData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal enriched SARIF: %w", err)
+	}
+	return enrichedData, nil
+}
+
+// enrichRule fills in the descriptions and default level of a rule from its pattern, and adds the pattern metadata
+// to properties.codacy
+func enrichRule(rule map[string]interface{}, pattern domain.PatternDefinition) {
+	setMissingText(rule, "shortDescription", pattern.Title)
+	setMissingText(rule, "fullDescription", pattern.Description)
+
+	if level, ok := sarifLevelsByCodacyLevel[strings.ToLower(pattern.Level)]; ok {
+		configuration, _ := rule["defaultConfiguration"].(map[string]interface{})
+		if configuration == nil {
+			configuration = make(map[string]interface{})
+			rule["defaultConfiguration"] = configuration
+		}
+		if _, ok := configuration["level"]; !ok {
+			configuration["level"] = level
+		}
+	}
+
+	codacy := map[string]interface{}{
+		"patternId": pattern.Id,
+		"category":  pattern.Category,
+		"level":     pattern.Level,
+		"severity":  patternSeverity(pattern),
+	}
+	if pattern.Title != "" {
+		codacy["title"] = pattern.Title
+	}
+	if pattern.TimeToFix > 0 {
+		codacy["timeToFix"] = pattern.TimeToFix
+	}
+	setCodacyProperties(rule, codacy)
+}
+
+// setMissingText sets a SARIF message property, e.g. shortDescription, unless the tool already set it
+func setMissingText(object map[string]interface{}, key string, text string) {
+	if text == "" {
+		return
+	}
+	if existing, ok := object[key].(map[string]interface{}); ok {
+		if current, _ := existing["text"].(string); current != "" {
+			return
+		}
+		existing["text"] = text
+		return
+	}
+	object[key] = map[string]interface{}{"text": text}
+}
+
+// setCodacyProperties adds the non-empty values to properties.codacy of a SARIF object
+func setCodacyProperties(object map[string]interface{}, values map[string]interface{}) {
+	properties, _ := object["properties"].(map[string]interface{})
+	if properties == nil {
+		properties = make(map[string]interface{})
+		object["properties"] = properties
+	}
+	codacy, _ := properties[codacyPropertiesKey].(map[string]interface{})
+	if codacy == nil {
+		codacy = make(map[string]interface{})
+		properties[codacyPropertiesKey] = codacy
+	}
+	for key, value := range values {
+		if value != "" {
+			codacy[key] = value
+		}
+	}
+}
+
+// patternSeverity returns the severity of a pattern, falling back to its level for patterns without one
+func patternSeverity(pattern domain.PatternDefinition) string {
+	if pattern.SeverityLevel != "" {
+		return pattern.SeverityLevel
+	}
+	return pattern.Level
+}
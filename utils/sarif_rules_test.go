@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrichSarifRules(t *testing.T) {
+	sarif := `{
+		"version": "2.1.0",
+		"runs": [
+			{
+				"tool": {"driver": {"name": "ESLint", "version": "8.57.0", "rules": [
+					{"id": "no-var", "shortDescription": {"text": "Require let or const"}, "helpUri": "https://eslint.org/docs/rules/no-var"},
+					{"id": "unknown-rule"}
+				]}},
+				"results": [
+					{"ruleId": "no-var", "message": {"text": "Unexpected var"}, "properties": {"tags": ["es6"]}},
+					{"ruleId": "semi", "message": {"text": "Missing semicolon"}},
+					{"ruleId": "unknown-rule", "message": {"text": "Unknown"}}
+				]
+			},
+			{
+				"tool": {"driver": {"name": "Trivy", "rules": null}},
+				"results": []
+			}
+		]
+	}`
+
+	patterns := map[string]domain.PatternDefinition{
+		"no-var": {Id: "ESLint8_no-var", Title: "Disallow var", Description: "Use let or const instead of var", Category: "BestPractice", Level: "Warning", SeverityLevel: "Medium", TimeToFix: 5},
+		"semi":   {Id: "ESLint8_semi", Title: "Require semicolons", Category: "CodeStyle", Level: "Info"},
+	}
+	lookup := func(driverName string, driverVersion string, ruleID string) (domain.PatternDefinition, bool) {
+		assert.Equal(t, "ESLint", driverName)
+		assert.Equal(t, "8.57.0", driverVersion)
+		pattern, ok := patterns[ruleID]
+		return pattern, ok
+	}
+
+	enriched, err := EnrichSarifRules([]byte(sarif), lookup)
+	require.NoError(t, err)
+
+	var report struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []map[string]interface{} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []map[string]interface{} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(enriched, &report))
+
+	rules := report.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, 3, "rules of results with a known pattern are added")
+
+	noVar := rules[0]
+	assert.Equal(t, map[string]interface{}{"text": "Require let or const"}, noVar["shortDescription"], "descriptions of the tool are kept")
+	assert.Equal(t, map[string]interface{}{"text": "Use let or const instead of var"}, noVar["fullDescription"])
+	assert.Equal(t, "https://eslint.org/docs/rules/no-var", noVar["helpUri"])
+	assert.Equal(t, map[string]interface{}{"level": "warning"}, noVar["defaultConfiguration"])
+	assert.Equal(t, map[string]interface{}{"codacy": map[string]interface{}{
+		"patternId": "ESLint8_no-var",
+		"title":     "Disallow var",
+		"category":  "BestPractice",
+		"level":     "Warning",
+		"severity":  "Medium",
+		"timeToFix": float64(5),
+	}}, noVar["properties"])
+
+	assert.Equal(t, map[string]interface{}{"id": "unknown-rule"}, rules[1])
+
+	semi := rules[2]
+	assert.Equal(t, "semi", semi["id"])
+	assert.Equal(t, map[string]interface{}{"text": "Require semicolons"}, semi["shortDescription"])
+	assert.Equal(t, map[string]interface{}{"level": "note"}, semi["defaultConfiguration"])
+
+	results := report.Runs[0].Results
+	assert.Equal(t, map[string]interface{}{
+		"tags":   []interface{}{"es6"},
+		"codacy": map[string]interface{}{"category": "BestPractice", "severity": "Medium"},
+	}, results[0]["properties"])
+	assert.Equal(t, map[string]interface{}{"codacy": map[string]interface{}{"category": "CodeStyle", "severity": "Info"}}, results[1]["properties"])
+	assert.Nil(t, results[2]["properties"])
+
+	assert.Nil(t, report.Runs[1].Tool.Driver.Rules, "runs without rules nor known patterns are unchanged")
+
+	toolResults, err := ParseSarifIssues(enriched, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "CodeStyle", toolResults[0].Issues[1].Category, "the category of the pattern is used when the tool reports none")
+}
+
+func TestEnrichSarifRulesInvalidData(t *testing.T) {
+	_, err := EnrichSarifRules([]byte("not json"), func(string, string, string) (domain.PatternDefinition, bool) {
+		return domain.PatternDefinition{}, false
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"codacy/cli-v2/domain"
+)
+
+const (
+	// ignoreDirective suppresses the matching issues on its line, and on the next one when it is alone on its line
+	ignoreDirective = "codacy:ignore"
+	// ignoreFileSuffix turns ignoreDirective into a directive suppressing the matching issues of the whole file
+	ignoreFileSuffix = "-file"
+	// justificationSeparator separates the targets of a directive from the reason for the suppression
+	justificationSeparator = "--"
+)
+
+// commentMarkers are the characters that open a comment in the supported languages
+const commentMarkers = "/*#-<!;%'{\""
+
+// commentTerminators are removed from the end of a directive, so it can be written in block comments
+var commentTerminators = []string{"*/", "-->", "--%>", "%>", "#}", "}}"}
+
+// suppressionTarget is a tool, optionally restricted to one of its rules, named in a directive
+type suppressionTarget struct {
+	tool string
+	rule string
+}
+
+// suppressionDirective is a codacy:ignore comment found in a source file
+type suppressionDirective struct {
+	line      int
+	wholeFile bool
+	// ownLine is set when the comment is alone on its line, so it applies to the next line
+	ownLine       bool
+	targets       []suppressionTarget
+	justification string
+}
+
+// parseSuppressionDirective parses the codacy:ignore or codacy:ignore-file directive of a line, in any comment style:
+//
+//	codacy:ignore
+//	codacy:ignore eslint/no-unused-vars, pylint -- reason for the suppression
+//	codacy:ignore-file trivy
+//
+// Without targets, every issue is suppressed.
+func parseSuppressionDirective(line string, lineNumber int) (suppressionDirective, bool) {
+	index := strings.Index(line, ignoreDirective)
+	if index < 0 {
+		return suppressionDirective{}, false
+	}
+	rest := line[index+len(ignoreDirective):]
+
+	directive := suppressionDirective{line: lineNumber, ownLine: isCommentPrefix(line[:index])}
+	if strings.HasPrefix(rest, ignoreFileSuffix) {
+		directive.wholeFile = true
+		rest = rest[len(ignoreFileSuffix):]
+	}
+	// The directive must be a word of its own, e.g. not codacy:ignored
+	if rest != "" && !unicode.IsSpace(rune(rest[0])) && !strings.HasPrefix(rest, "*/") && !strings.HasPrefix(rest, "-->") {
+		return suppressionDirective{}, false
+	}
+
+	rest = strings.TrimSpace(rest)
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, terminator := range commentTerminators {
+			if strings.HasSuffix(rest, terminator) {
+				rest = strings.TrimSpace(strings.TrimSuffix(rest, terminator))
+				trimmed = true
+			}
+		}
+	}
+
+	targets, justification, _ := strings.Cut(rest, justificationSeparator)
+	directive.justification = strings.TrimSpace(justification)
+	for _, target := range strings.FieldsFunc(targets, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		tool, rule, _ := strings.Cut(target, "/")
+		directive.targets = append(directive.targets, suppressionTarget{tool: tool, rule: rule})
+	}
+
+	return directive, true
+}
+
+// isCommentPrefix checks if the text before a directive only opens a comment, e.g. "  //" or "<!--"
+func isCommentPrefix(prefix string) bool {
+	return strings.TrimFunc(prefix, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(commentMarkers, r)
+	}) == ""
+}
+
+// matches checks if the directive suppresses an issue, without considering its location
+func (d suppressionDirective) matches(issue domain.Issue) bool {
+	if len(d.targets) == 0 {
+		return true
+	}
+	for _, target := range d.targets {
+		if MatchesToolName(issue.Tool, target.tool) && (target.rule == "" || strings.EqualFold(target.rule, issue.PatternID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// description explains the suppression in the SARIF report
+func (d suppressionDirective) description() string {
+	if d.justification != "" {
+		return d.justification
+	}
+	name := ignoreDirective
+	if d.wholeFile {
+		name += ignoreFileSuffix
+	}
+	return fmt.Sprintf("%s comment on line %d", name, d.line)
+}
+
+// MatchesToolName checks if a tool name given in a directive, e.g. "eslint", names the SARIF driver of an issue,
+// e.g. "ESLint9". Names are compared ignoring case and punctuation, and the driver name may have a suffix.
+func MatchesToolName(driverName string, toolName string) bool {
+	normalize := func(name string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, name)
+	}
+	normalizedToolName := normalize(toolName)
+	return normalizedToolName != "" && strings.HasPrefix(normalize(driverName), normalizedToolName)
+}
+
+// fileSuppressions holds the directives of a source file
+type fileSuppressions struct {
+	wholeFile []suppressionDirective
+	byLine    map[int][]suppressionDirective
+}
+
+// parseFileSuppressions finds the directives of a file. A codacy:ignore directive applies to its own line,
+// and to the next line when the comment is alone on its line, i.e. placed above the code.
+func parseFileSuppressions(lines []string) fileSuppressions {
+	suppressions := fileSuppressions{byLine: make(map[int][]suppressionDirective)}
+	for i, line := range lines {
+		directive, ok := parseSuppressionDirective(line, i+1)
+		if !ok {
+			continue
+		}
+		if directive.wholeFile {
+			suppressions.wholeFile = append(suppressions.wholeFile, directive)
+			continue
+		}
+		suppressions.byLine[i+1] = append(suppressions.byLine[i+1], directive)
+		if directive.ownLine {
+			suppressions.byLine[i+2] = append(suppressions.byLine[i+2], directive)
+		}
+	}
+	return suppressions
+}
+
+// find returns the directive suppressing an issue of the file, if any
+func (s fileSuppressions) find(issue domain.Issue) (suppressionDirective, bool) {
+	for _, directive := range s.wholeFile {
+		if directive.matches(issue) {
+			return directive, true
+		}
+	}
+	if issue.Region.StartLine > 0 {
+		for _, directive := range s.byLine[issue.Region.StartLine] {
+			if directive.matches(issue) {
+				return directive, true
+			}
+		}
+	}
+	return suppressionDirective{}, false
+}
+
+// ApplyInlineSuppressions marks the results suppressed by codacy:ignore comments in the analyzed files with a SARIF
+// suppression of kind inSource. Suppressed results are kept in the report, so audits can still see them.
+// It returns the updated SARIF and the number of newly suppressed results.
+func ApplyInlineSuppressions(sarifData []byte, baseDir string) ([]byte, int, error) {
+	sources := NewSourceFiles(baseDir)
+	suppressionsByFile := make(map[string]fileSuppressions)
+	suppressed := 0
+
+	updatedData, err := editSarifResults(sarifData, sources, func(issue domain.Issue, result map[string]interface{}) bool {
+		if issue.Path == "" || issue.Suppressed {
+			return true
+		}
+
+		suppressions, ok := suppressionsByFile[issue.Path]
+		if !ok {
+			suppressions = parseFileSuppressions(sources.Lines(issue.Path))
+			suppressionsByFile[issue.Path] = suppressions
+		}
+
+		if directive, ok := suppressions.find(issue); ok {
+			existing, _ := result["suppressions"].([]interface{})
+			result["suppressions"] = append(existing, map[string]interface{}{
+				"kind":          "inSource",
+				"justification": directive.description(),
+			})
+			suppressed++
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return updatedData, suppressed, nil
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codacy/cli-v2/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuppressionDirective(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected suppressionDirective
+		found    bool
+	}{
+		{
+			name:     "all tools",
+			line:     "x = 1 # codacy:ignore",
+			expected: suppressionDirective{line: 3},
+			found:    true,
+		},
+		{
+			name: "tools and rules with justification",
+			line: "// codacy:ignore eslint/@typescript-eslint/no-explicit-any, pylint -- legacy API",
+			expected: suppressionDirective{line: 3, ownLine: true, justification: "legacy API", targets: []suppressionTarget{
+				{tool: "eslint", rule: "@typescript-eslint/no-explicit-any"},
+				{tool: "pylint"},
+			}},
+			found: true,
+		},
+		{
+			name:     "whole file in a block comment",
+			line:     "/* codacy:ignore-file trivy */",
+			expected: suppressionDirective{line: 3, wholeFile: true, ownLine: true, targets: []suppressionTarget{{tool: "trivy"}}},
+			found:    true,
+		},
+		{
+			name:     "html comment",
+			line:     "<!-- codacy:ignore-->",
+			expected: suppressionDirective{line: 3, ownLine: true},
+			found:    true,
+		},
+		{
+			name: "other word",
+			line: "// codacy:ignored",
+		},
+		{
+			name: "no directive",
+			line: "var a = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directive, found := parseSuppressionDirective(tt.line, 3)
+			assert.Equal(t, tt.found, found)
+			if tt.found {
+				assert.Equal(t, tt.expected, directive)
+			}
+		})
+	}
+}
+
+func TestSuppressionDirectiveMatches(t *testing.T) {
+	issue := domain.Issue{Tool: "ESLint9", PatternID: "no-unused-vars"}
+
+	directive, _ := parseSuppressionDirective("// codacy:ignore eslint/no-unused-vars", 1)
+	assert.True(t, directive.matches(issue))
+
+	directive, _ = parseSuppressionDirective("// codacy:ignore ESLINT", 1)
+	assert.True(t, directive.matches(issue))
+
+	directive, _ = parseSuppressionDirective("// codacy:ignore eslint/semi", 1)
+	assert.False(t, directive.matches(issue))
+
+	directive, _ = parseSuppressionDirective("// codacy:ignore pmd", 1)
+	assert.False(t, directive.matches(issue))
+
+	assert.True(t, MatchesToolName("Opengrep OSS", "opengrep"))
+	assert.True(t, MatchesToolName("codacy-enigma-cli", "codacy-enigma-cli"))
+	assert.False(t, MatchesToolName("ESLint", ""))
+}
+
+func TestApplyInlineSuppressions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte(
+		"// codacy:ignore eslint/no-var -- kept for old browsers\n"+
+			"var a = 1\n"+
+			"var b = 2 // codacy:ignore\n"+
+			"var c = 3\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("# codacy:ignore-file trivy\nFROM alpine\n"), 0644))
+
+	sarifData := sarifWithResults(t, "ESLint",
+		resultAt("no-var", "app.js", 2),
+		resultAt("semi", "app.js", 2),
+		resultAt("no-var", "app.js", 3),
+		resultAt("no-var", "app.js", 4),
+	)
+	updated, suppressed, err := ApplyInlineSuppressions(sarifData, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, suppressed)
+
+	toolResults, err := ParseSarifIssues(updated, dir)
+	require.NoError(t, err)
+	require.Len(t, toolResults[0].Issues, 4, "suppressed results are kept")
+	var suppressedRules []string
+	for _, issue := range toolResults[0].Issues {
+		if issue.Suppressed {
+			suppressedRules = append(suppressedRules, issue.PatternID+"@"+issue.Path)
+		}
+	}
+	assert.Equal(t, []string{"no-var@app.js", "no-var@app.js"}, suppressedRules)
+	assert.Contains(t, string(updated), `"justification": "kept for old browsers"`)
+	assert.Contains(t, string(updated), `"kind": "inSource"`)
+
+	// Applying the suppressions again doesn't add more
+	_, suppressed, err = ApplyInlineSuppressions(updated, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, suppressed)
+
+	_, suppressed, err = ApplyInlineSuppressions(sarifWithResults(t, "Trivy", resultAt("DS001", "Dockerfile", 2)), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, suppressed)
+}
+
+func TestSuppressedIssuesAreNotPartOfTheBaseline(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("var a = 1 // codacy:ignore\nvar b = 2\n"), 0644))
+
+	sarifData, _, err := ApplyInlineSuppressions(sarifWithResults(t, "ESLint",
+		resultAt("no-var", "app.js", 1),
+		resultAt("no-var", "app.js", 2),
+	), dir)
+	require.NoError(t, err)
+
+	baseline, err := NewBaseline(sarifData, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, baseline.SuppressedIssues)
+}